@@ -688,6 +688,106 @@ func TestAlign_content_spacearound(t *testing.T) {
 	assertFloatEqual(t, 10, rootChild4.LayoutGetHeight())
 }
 
+func TestAlign_content_spaceevenly(t *testing.T) {
+
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetAlignContent(AlignSpaceEvenly)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(140)
+	root.StyleSetHeight(120)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(50)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(50)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(50)
+	rootChild2.StyleSetHeight(10)
+	root.InsertChild(rootChild2, 2)
+
+	rootChild3 := NewNodeWithConfig(config)
+	rootChild3.StyleSetWidth(50)
+	rootChild3.StyleSetHeight(10)
+	root.InsertChild(rootChild3, 3)
+
+	rootChild4 := NewNodeWithConfig(config)
+	rootChild4.StyleSetWidth(50)
+	rootChild4.StyleSetHeight(10)
+	root.InsertChild(rootChild4, 4)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 140, root.LayoutGetWidth())
+	assertFloatEqual(t, 120, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 23, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 23, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 55, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 55, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild3.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild4.LayoutGetLeft())
+	assertFloatEqual(t, 88, rootChild4.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild4.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild4.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 140, root.LayoutGetWidth())
+	assertFloatEqual(t, 120, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 90, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 23, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 23, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 90, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 55, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 40, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 55, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild3.LayoutGetHeight())
+
+	assertFloatEqual(t, 90, rootChild4.LayoutGetLeft())
+	assertFloatEqual(t, 88, rootChild4.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild4.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild4.LayoutGetHeight())
+}
+
 func TestAlign_content_stretch_row(t *testing.T) {
 	config := NewConfig()
 
@@ -0,0 +1,86 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutGetBox(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	child := NewNodeWithConfig(config)
+	child.StyleSetHeight(40)
+	child.StyleSetMargin(EdgeStart, 5)
+	child.StyleSetMargin(EdgeTop, 2)
+	child.StyleSetBorder(EdgeStart, 1)
+	child.StyleSetBorder(EdgeTop, 3)
+	child.StyleSetPadding(EdgeEnd, 4)
+	child.StyleSetPadding(EdgeBottom, 6)
+	root.InsertChild(child, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, Rect{15, 12, 75, 40}, child.LayoutGetBox(BoxBorder))
+	assert.Equal(t, Rect{16, 15, 74, 37}, child.LayoutGetBox(BoxPadding))
+	assert.Equal(t, Rect{16, 15, 70, 31}, child.LayoutGetBox(BoxContent))
+	assert.Equal(t, Rect{10, 10, 80, 42}, child.LayoutGetBox(BoxMargin))
+	assert.Equal(t, Rect{10, 10, 80, 80}, root.LayoutGetBox(BoxContent))
+	assert.Equal(t, "padding-box", BoxToString(BoxPadding))
+}
+
+func TestLayoutGetBox_rtl(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	child := NewNodeWithConfig(config)
+	child.StyleSetHeight(40)
+	child.StyleSetMargin(EdgeStart, 5)
+	child.StyleSetMargin(EdgeTop, 2)
+	child.StyleSetBorder(EdgeStart, 1)
+	child.StyleSetBorder(EdgeTop, 3)
+	child.StyleSetPadding(EdgeEnd, 4)
+	child.StyleSetPadding(EdgeBottom, 6)
+	root.InsertChild(child, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assert.Equal(t, Rect{10, 12, 75, 40}, child.LayoutGetBox(BoxBorder))
+	assert.Equal(t, Rect{10, 15, 74, 37}, child.LayoutGetBox(BoxPadding))
+	assert.Equal(t, Rect{14, 15, 70, 31}, child.LayoutGetBox(BoxContent))
+	assert.Equal(t, Rect{10, 10, 80, 42}, child.LayoutGetBox(BoxMargin))
+}
+
+func TestLayoutGetAbsoluteBox(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	child := NewNodeWithConfig(config)
+	child.StyleSetHeight(40)
+	child.StyleSetMargin(EdgeStart, 5)
+	child.StyleSetMargin(EdgeTop, 2)
+	child.StyleSetBorder(EdgeStart, 1)
+	child.StyleSetBorder(EdgeTop, 3)
+	child.StyleSetPadding(EdgeEnd, 4)
+	child.StyleSetPadding(EdgeBottom, 6)
+	root.InsertChild(child, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	leaf := NewNodeWithConfig(root.Config)
+	leaf.StyleSetHeight(5)
+	child.InsertChild(leaf, 0)
+	root.StyleSetPosition(EdgeLeft, 100)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, Rect{1, 3, 70, 5}, leaf.LayoutGetBox(BoxBorder))
+	assert.Equal(t, Rect{116, 15, 70, 5}, leaf.LayoutGetAbsoluteBox(BoxBorder))
+	assert.Equal(t, Rect{116, 15, 70, 31}, child.LayoutGetAbsoluteBox(BoxContent))
+	assert.Equal(t, root.LayoutGetBox(BoxMargin), root.LayoutGetAbsoluteBox(BoxMargin))
+}
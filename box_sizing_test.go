@@ -0,0 +1,134 @@
+package flex
+
+import "testing"
+
+func TestBox_sizing_content_box(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetBoxSizing(BoxSizingContentBox)
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetBorder(EdgeAll, 5)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 130, root.LayoutGetWidth())
+	assertFloatEqual(t, 130, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 15, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 15, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+
+	root.StyleSetBoxSizing(BoxSizingBorderBox)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+	assertFloatEqual(t, 70, rootChild0.LayoutGetWidth())
+}
+
+func TestBox_sizing_content_box_percent(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetBoxSizing(BoxSizingContentBox)
+	rootChild0.StyleSetPadding(EdgeAll, 10)
+	rootChild0.StyleSetWidthPercent(50)
+	rootChild0.StyleSetHeightPercent(25)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 120, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 70, rootChild0.LayoutGetHeight())
+}
+
+func TestBox_sizing_content_box_min_max(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetBoxSizing(BoxSizingContentBox)
+	rootChild0.StyleSetPadding(EdgeAll, 10)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMaxWidth(50)
+	rootChild0.StyleSetMinHeight(90)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetBoxSizing(BoxSizingContentBox)
+	rootChild1.StyleSetBorder(EdgeAll, 5)
+	rootChild1.StyleSetMinWidth(40)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 110, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 70, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetHeight())
+}
+
+func TestBox_sizing_content_box_flex_basis(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetBoxSizing(BoxSizingContentBox)
+	rootChild0.StyleSetPadding(EdgeHorizontal, 10)
+	rootChild0.StyleSetFlexBasis(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 70, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 130, rootChild1.LayoutGetWidth())
+}
+
+func TestBox_sizing_config_default(t *testing.T) {
+	config := NewConfig()
+	config.BoxSizing = BoxSizingContentBox
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetBoxSizing(BoxSizingBorderBox)
+	rootChild0.StyleSetPadding(EdgeAll, 10)
+	rootChild0.StyleSetWidth(50)
+	rootChild0.StyleSetHeight(50)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 120, root.LayoutGetWidth())
+	assertFloatEqual(t, 120, root.LayoutGetHeight())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetHeight())
+}
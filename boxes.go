@@ -0,0 +1,121 @@
+package flex
+
+// boxState is the absolute layout of a node last seen by updateBoxes
+type boxState struct {
+	parent *Node
+	// layoutVersion is the layoutVersion of the node when it was seen
+	layoutVersion uint64
+	// box is the absolute border box
+	box Rect
+	// visible is box clipped by ancestors, empty for display: contents
+	visible Rect
+	// childClip is the clip of children, valid if childClipped
+	childClip    Rect
+	childClipped bool
+	children     []*Node
+}
+
+// boxObserver keeps the box states of a tree for updateBoxes
+type boxObserver interface {
+	boxState(node *Node) *boxState
+	// setBoxState sets the state of node, nil to forget it
+	setBoxState(node *Node, state *boxState)
+	// visibleChanged is called after the visible box of node changed, from
+	// or to empty for added and removed nodes
+	visibleChanged(node *Node, old Rect, visible Rect)
+}
+
+// updateBoxes updates the box states of node and its descendants after a
+// layout, with node at parentLeft, parentTop and clipped by the clip of its
+// parent. Unless force is set, nodes without a new layout which didn't move
+// are skipped with their subtree.
+func updateBoxes(o boxObserver, node *Node, parent *Node, parentLeft float32, parentTop float32,
+	clip Rect, clipped bool, force bool) {
+	state := o.boxState(node)
+	if node.Style.Display == DisplayNone {
+		if state != nil {
+			removeBoxes(o, node)
+		}
+		return
+	}
+	box := node.LayoutGetBox(BoxBorder)
+	box.Left += parentLeft
+	box.Top += parentTop
+	if state != nil && !force && state.layoutVersion == node.layoutVersion && state.parent == parent &&
+		state.box.Left == box.Left && state.box.Top == box.Top {
+		return
+	}
+	if state == nil {
+		state = &boxState{}
+		o.setBoxState(node, state)
+	}
+
+	contents := node.Style.Display == DisplayContents
+	visible := box
+	if contents {
+		visible = Rect{}
+	} else if clipped {
+		visible = box.Intersection(clip)
+	}
+	old := state.visible
+	state.parent, state.layoutVersion, state.box, state.visible = parent, node.layoutVersion, box, visible
+	if visible != old {
+		o.visibleChanged(node, old, visible)
+	}
+
+	// a changed clip changes the visible boxes of the whole subtree
+	childClip, childClipped := clip, clipped
+	if !contents && node.Style.Overflow != OverflowVisible {
+		childClip, childClipped = box, true
+		if clipped {
+			childClip = box.Intersection(clip)
+		}
+	}
+	if childClipped != state.childClipped || childClip != state.childClip {
+		force = true
+	}
+	state.childClip, state.childClipped = childClip, childClipped
+
+	if !nodeListEqual(state.children, node.Children) {
+		current := make(map[*Node]bool, len(node.Children))
+		for _, child := range node.Children {
+			current[child] = true
+		}
+		for _, child := range state.children {
+			if s := o.boxState(child); !current[child] && s != nil && s.parent == node {
+				removeBoxes(o, child)
+			}
+		}
+		state.children = append(state.children[:0], node.Children...)
+	}
+	for _, child := range node.Children {
+		updateBoxes(o, child, node, box.Left, box.Top, childClip, childClipped, force)
+	}
+}
+
+// removeBoxes forgets the states of node and its descendants, which are no
+// longer shown
+func removeBoxes(o boxObserver, node *Node) {
+	state := o.boxState(node)
+	o.setBoxState(node, nil)
+	if !state.visible.IsEmpty() {
+		o.visibleChanged(node, state.visible, Rect{})
+	}
+	for _, child := range state.children {
+		if s := o.boxState(child); s != nil && s.parent == node {
+			removeBoxes(o, child)
+		}
+	}
+}
+
+func nodeListEqual(a []*Node, b []*Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,269 @@
+package flex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CalcOp is the operation of a Calc expression
+type CalcOp int
+
+const (
+	// CalcOpValue is a point or percent value
+	CalcOpValue CalcOp = iota
+	// CalcOpAdd is "a + b"
+	CalcOpAdd
+	// CalcOpSub is "a - b"
+	CalcOpSub
+	// CalcOpMul is "a * factor"
+	CalcOpMul
+	// CalcOpDiv is "a / factor"
+	CalcOpDiv
+	// CalcOpMin is "min(a, b, ...)"
+	CalcOpMin
+	// CalcOpMax is "max(a, b, ...)"
+	CalcOpMax
+	// CalcOpClamp is "clamp(min, value, max)"
+	CalcOpClamp
+	// CalcOpVar is "var(--name, fallback)"
+	CalcOpVar
+)
+
+// Calc is an expression used by a Value with UnitCalc. It is evaluated
+// against the parent size, like a percent value.
+type Calc struct {
+	Op CalcOp
+	// Value is a point or percent value for CalcOpValue
+	Value Value
+	// Factor is the multiplier or divisor for CalcOpMul and CalcOpDiv
+	Factor float32
+	// Name is the custom property name for CalcOpVar, like "--spacing"
+	Name string
+	Args []*Calc
+}
+
+// ValueCalc returns a Value evaluated from calc
+func ValueCalc(calc *Calc) Value {
+	return Value{Value: Undefined, Unit: UnitCalc, Calc: calc}
+}
+
+// CalcPoint returns a point value
+func CalcPoint(value float32) *Calc {
+	return &Calc{Op: CalcOpValue, Value: Value{Value: value, Unit: UnitPoint}}
+}
+
+// CalcValue returns a value in any unit that has a number, like UnitVw or UnitEm
+func CalcValue(value Value) *Calc {
+	return &Calc{Op: CalcOpValue, Value: value}
+}
+
+// CalcPercent returns a percent value
+func CalcPercent(value float32) *Calc {
+	return &Calc{Op: CalcOpValue, Value: Value{Value: value, Unit: UnitPercent}}
+}
+
+// CalcAdd returns a + b
+func CalcAdd(a *Calc, b *Calc) *Calc {
+	return &Calc{Op: CalcOpAdd, Args: []*Calc{a, b}}
+}
+
+// CalcSub returns a - b
+func CalcSub(a *Calc, b *Calc) *Calc {
+	return &Calc{Op: CalcOpSub, Args: []*Calc{a, b}}
+}
+
+// CalcMul returns a * factor
+func CalcMul(a *Calc, factor float32) *Calc {
+	return &Calc{Op: CalcOpMul, Factor: factor, Args: []*Calc{a}}
+}
+
+// CalcDiv returns a / divisor. A zero or undefined divisor makes the
+// expression resolve to undefined.
+func CalcDiv(a *Calc, divisor float32) *Calc {
+	return &Calc{Op: CalcOpDiv, Factor: divisor, Args: []*Calc{a}}
+}
+
+// CalcMin returns the smallest of args
+func CalcMin(args ...*Calc) *Calc {
+	return &Calc{Op: CalcOpMin, Args: args}
+}
+
+// CalcMax returns the largest of args
+func CalcMax(args ...*Calc) *Calc {
+	return &Calc{Op: CalcOpMax, Args: args}
+}
+
+// CalcClamp returns value limited to the range from min to max. min wins if
+// it is larger than max.
+func CalcClamp(min *Calc, value *Calc, max *Calc) *Calc {
+	return &Calc{Op: CalcOpClamp, Args: []*Calc{min, value, max}}
+}
+
+// CalcVar returns the value of a custom property, or fallback if no node
+// or ancestor defines it. fallback can be nil.
+func CalcVar(name string, fallback *Calc) *Calc {
+	calc := &Calc{Op: CalcOpVar, Name: name}
+	if fallback != nil {
+		calc.Args = []*Calc{fallback}
+	}
+	return calc
+}
+
+// ValueVar returns a Value evaluated from the custom property name, like
+// "var(--spacing)"
+func ValueVar(name string) Value {
+	return ValueCalc(CalcVar(name, nil))
+}
+
+// resolve evaluates calc against parentSize. Percent values resolved against
+// an undefined parent size make the whole expression undefined.
+func (calc *Calc) resolve(parentSize float32, bases *unitBases) float32 {
+	switch calc.Op {
+	case CalcOpValue:
+		return resolveValueWithBases(&calc.Value, parentSize, bases)
+	case CalcOpAdd:
+		return calc.Args[0].resolve(parentSize, bases) + calc.Args[1].resolve(parentSize, bases)
+	case CalcOpSub:
+		return calc.Args[0].resolve(parentSize, bases) - calc.Args[1].resolve(parentSize, bases)
+	case CalcOpMul:
+		return calc.Args[0].resolve(parentSize, bases) * calc.Factor
+	case CalcOpDiv:
+		if calc.Factor == 0 || FloatIsUndefined(calc.Factor) {
+			return Undefined
+		}
+		return calc.Args[0].resolve(parentSize, bases) / calc.Factor
+	case CalcOpMin, CalcOpMax:
+		if len(calc.Args) == 0 {
+			return Undefined
+		}
+		result := calc.Args[0].resolve(parentSize, bases)
+		for _, arg := range calc.Args[1:] {
+			v := arg.resolve(parentSize, bases)
+			if FloatIsUndefined(v) {
+				return Undefined
+			}
+			if (calc.Op == CalcOpMin && v < result) || (calc.Op == CalcOpMax && v > result) {
+				result = v
+			}
+		}
+		return result
+	case CalcOpClamp:
+		min := calc.Args[0].resolve(parentSize, bases)
+		value := calc.Args[1].resolve(parentSize, bases)
+		max := calc.Args[2].resolve(parentSize, bases)
+		if FloatIsUndefined(min) || FloatIsUndefined(value) || FloatIsUndefined(max) {
+			return Undefined
+		}
+		if value > max {
+			value = max
+		}
+		if value < min {
+			value = min
+		}
+		return value
+	case CalcOpVar:
+		return resolveCustomProperty(calc, parentSize, bases)
+	}
+	return Undefined
+}
+
+// hasPercent returns true if calc depends on the parent size. Custom
+// properties are assumed to depend on it.
+func (calc *Calc) hasPercent() bool {
+	switch calc.Op {
+	case CalcOpValue:
+		return calc.Value.Unit == UnitPercent
+	case CalcOpVar:
+		return true
+	}
+	for _, arg := range calc.Args {
+		if arg.hasPercent() {
+			return true
+		}
+	}
+	return false
+}
+
+// usesViewport returns true if calc depends on the viewport size. Custom
+// properties are assumed to depend on it.
+func (calc *Calc) usesViewport() bool {
+	switch calc.Op {
+	case CalcOpValue:
+		return valueUsesViewport(&calc.Value)
+	case CalcOpVar:
+		return true
+	}
+	for _, arg := range calc.Args {
+		if arg.usesViewport() {
+			return true
+		}
+	}
+	return false
+}
+
+func calcEqual(a *Calc, b *Calc) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil || a.Op != b.Op || !feq(a.Factor, b.Factor) || a.Name != b.Name ||
+		!ValueEqual(a.Value, b.Value) || len(a.Args) != len(b.Args) {
+		return false
+	}
+	for i := range a.Args {
+		if !calcEqual(a.Args[i], b.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns calc in CSS syntax
+func (calc *Calc) String() string {
+	switch calc.Op {
+	case CalcOpValue, CalcOpAdd, CalcOpSub, CalcOpMul, CalcOpDiv:
+		return "calc(" + calc.expr() + ")"
+	}
+	return calc.expr()
+}
+
+func (calc *Calc) expr() string {
+	switch calc.Op {
+	case CalcOpValue:
+		return fmt.Sprintf("%g%s", calc.Value.Value, unitSuffix(calc.Value.Unit))
+	case CalcOpAdd:
+		return calc.Args[0].term() + " + " + calc.Args[1].term()
+	case CalcOpSub:
+		return calc.Args[0].term() + " - " + calc.Args[1].term()
+	case CalcOpMul:
+		return fmt.Sprintf("%s * %g", calc.Args[0].term(), calc.Factor)
+	case CalcOpDiv:
+		return fmt.Sprintf("%s / %g", calc.Args[0].term(), calc.Factor)
+	case CalcOpVar:
+		if len(calc.Args) == 0 {
+			return "var(" + calc.Name + ")"
+		}
+		return "var(" + calc.Name + ", " + calc.Args[0].expr() + ")"
+	}
+
+	name := "min"
+	if calc.Op == CalcOpMax {
+		name = "max"
+	} else if calc.Op == CalcOpClamp {
+		name = "clamp"
+	}
+	args := make([]string, len(calc.Args))
+	for i, arg := range calc.Args {
+		args[i] = arg.expr()
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// term returns calc as an operand, in parentheses if it is an arithmetic
+// expression.
+func (calc *Calc) term() string {
+	switch calc.Op {
+	case CalcOpAdd, CalcOpSub, CalcOpMul, CalcOpDiv:
+		return "(" + calc.expr() + ")"
+	}
+	return calc.expr()
+}
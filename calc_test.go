@@ -0,0 +1,179 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalc_width_percent_minus_points(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthCalc(CalcSub(CalcPercent(100), CalcPoint(48)))
+	rootChild0.StyleSetHeightCalc(CalcDiv(CalcPercent(100), 2))
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 152, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 48, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 152, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetHeight())
+}
+
+func TestCalc_clamp(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(1000)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthCalc(CalcClamp(CalcPoint(200), CalcPercent(30), CalcPoint(400)))
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 300, rootChild0.LayoutGetWidth())
+
+	root.StyleSetWidth(500)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 200, rootChild0.LayoutGetWidth())
+
+	root.StyleSetWidth(2000)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 400, rootChild0.LayoutGetWidth())
+}
+
+func TestCalc_min_max_constraints(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(300)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMaxWidthCalc(CalcMin(CalcPercent(50), CalcPoint(120)))
+	rootChild0.StyleSetMinHeightCalc(CalcMax(CalcPercent(10), CalcPoint(40)))
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexBasisCalc(CalcAdd(CalcPercent(10), CalcPoint(5)))
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 120, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+	assertFloatEqual(t, 120, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 35, rootChild1.LayoutGetWidth())
+}
+
+func TestCalc_margin_padding_position(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetMarginCalc(EdgeLeft, CalcMul(CalcPercent(10), 2))
+	rootChild0.StyleSetPaddingCalc(EdgeTop, CalcSub(CalcPercent(10), CalcPoint(5)))
+	rootChild0.StyleSetPositionCalc(EdgeTop, CalcAdd(CalcPercent(5), CalcPoint(1)))
+	rootChild0.StyleSetWidth(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 40, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 11, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 25, rootChild0.LayoutGetHeight())
+	assertFloatEqual(t, 15, rootChild0Child0.LayoutGetTop())
+}
+
+func TestCalc_percent_of_undefined_parent(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(10)
+	rootChild0.StyleSetHeightCalc(CalcAdd(CalcPercent(50), CalcPoint(10)))
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetHeight(30)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 30, rootChild0.LayoutGetHeight())
+}
+
+func TestCalc_string(t *testing.T) {
+	assert.Equal(t, "calc(100% - 48px)", CalcSub(CalcPercent(100), CalcPoint(48)).String())
+	assert.Equal(t, "clamp(200px, 30%, 400px)", CalcClamp(CalcPoint(200), CalcPercent(30), CalcPoint(400)).String())
+	assert.Equal(t, "calc((10% + 5px) * 2)", CalcMul(CalcAdd(CalcPercent(10), CalcPoint(5)), 2).String())
+	assert.Equal(t, "min(50%, 100px - 10%)", CalcMin(CalcPercent(50), CalcSub(CalcPoint(100), CalcPercent(10))).String())
+}
+
+func TestCalc_setter_marks_dirty_on_change(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidthCalc(CalcSub(CalcPercent(100), CalcPoint(48)))
+	CalculateLayout(root, 100, 100, DirectionLTR)
+	assert.False(t, root.IsDirty)
+
+	root.StyleSetWidthCalc(CalcSub(CalcPercent(100), CalcPoint(48)))
+	assert.False(t, root.IsDirty)
+
+	root.StyleSetWidthCalc(CalcSub(CalcPercent(100), CalcPoint(40)))
+	assert.True(t, root.IsDirty)
+}
+
+func TestCalcDiv_zero_divisor_is_undefined(t *testing.T) {
+	config := NewConfig()
+	for _, divisor := range []float32{0, Undefined} {
+		root := NewNodeWithConfig(config)
+		root.StyleSetWidth(100)
+		root.StyleSetHeight(100)
+
+		rootChild0 := NewNodeWithConfig(config)
+		rootChild0.StyleSetWidthCalc(CalcDiv(CalcPoint(10), divisor))
+		rootChild0.StyleSetHeight(10)
+		root.InsertChild(rootChild0, 0)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+		assert.Equal(t, float32(100), rootChild0.LayoutGetWidth())
+	}
+}
+
+func TestCalc_min_content_contribution(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(500)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthMinContent()
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetWidthCalc(CalcAdd(CalcPoint(10), CalcPoint(2)))
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, float32(12), rootChild0.LayoutGetWidth())
+	assert.Equal(t, float32(12), rootChild0Child0.LayoutGetWidth())
+}
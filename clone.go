@@ -0,0 +1,105 @@
+package flex
+
+// Clone returns a copy of node with the same style, children, functions,
+// config and context. The clone has no parent. Its children are shared with
+// node and keep node as their parent, so laying out the clone changes them;
+// use CloneRecursive to compute layouts without touching node. Unless
+// options has CloneOptionsKeepLayout, the clone is dirty and has no layout.
+func (node *Node) Clone(options CloneOptions) *Node {
+	clone := &Node{}
+	*clone = *node
+	clone.Parent = nil
+	clone.NextChild = nil
+	if node.Children != nil {
+		clone.Children = append([]*Node(nil), node.Children...)
+	}
+	if node.classes != nil {
+		clone.classes = append([]string(nil), node.classes...)
+	}
+	if node.baseStyle != nil {
+		base := *node.baseStyle
+		clone.baseStyle = &base
+	}
+	if node.appliedStyle != nil {
+		applied := *node.appliedStyle
+		clone.appliedStyle = &applied
+	}
+	// the clone may get another parent, which changes the inherited bases
+	clone.bases = nil
+
+	// resolved dimensions point into the style or the intrinsic dimensions
+	for dim := range node.resolvedDimensions {
+		switch node.resolvedDimensions[dim] {
+		case &node.Style.Dimensions[dim]:
+			clone.resolvedDimensions[dim] = &clone.Style.Dimensions[dim]
+		case &node.Style.MaxDimensions[dim]:
+			clone.resolvedDimensions[dim] = &clone.Style.MaxDimensions[dim]
+		case &node.intrinsicDimensions[dim]:
+			clone.resolvedDimensions[dim] = &clone.intrinsicDimensions[dim]
+		}
+	}
+
+	if options&CloneOptionsKeepLayout == 0 {
+		clone.Layout = nodeDefaults.Layout
+		clone.hasNewLayout = nodeDefaults.hasNewLayout
+		clone.layoutVersion = nodeDefaults.layoutVersion
+		clone.frame = nodeDefaults.frame
+		clone.frameVersion = nodeDefaults.frameVersion
+		clone.IsDirty = true
+	}
+	return clone
+}
+
+// CloneRecursive returns a copy of node and all its descendants, see Clone.
+// The children of each clone are clones with Parent set to it, so the copy
+// can be changed and laid out without affecting node.
+func (node *Node) CloneRecursive(options CloneOptions) *Node {
+	clone := node.Clone(options)
+	for i, child := range clone.Children {
+		childClone := child.CloneRecursive(options)
+		childClone.Parent = clone
+		clone.Children[i] = childClone
+	}
+	return clone
+}
+
+// MutableChild returns the child at idx for changing it. In copy-on-write
+// mode (Config.CopyOnWrite) a child shared with another tree is first
+// replaced by a clone, so changes to it and dirty marking don't reach the
+// other tree. To change a node of a shared subtree, take MutableChild of
+// each node on the path to it.
+func (node *Node) MutableChild(idx int) *Node {
+	return nodeChild(node, idx)
+}
+
+// nodeChild returns child i of node, replacing it by a clone first if it
+// is shared in copy-on-write mode
+func nodeChild(node *Node, i int) *Node {
+	child := node.Children[i]
+	if child.Parent == node || node.Config == nil || !node.Config.CopyOnWrite {
+		return child
+	}
+	var clone *Node
+	if node.Config.CloneNodeFunc != nil {
+		clone = node.Config.CloneNodeFunc(child, node, i)
+	} else {
+		clone = child.Clone(CloneOptionsKeepLayout)
+	}
+	clone.Parent = node
+	node.Children[i] = clone
+	return clone
+}
+
+// nodeOwnChildren replaces shared children of node, and of its children
+// with display: contents, which are laid out by node, by clones
+func nodeOwnChildren(node *Node) {
+	if node.Config == nil || !node.Config.CopyOnWrite {
+		return
+	}
+	for i := range node.Children {
+		child := nodeChild(node, i)
+		if child.Style.Display == DisplayContents {
+			nodeOwnChildren(child)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone_shallow_shares_children(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidth(100)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+
+	leaf := NewNodeWithConfig(root.Config)
+	leaf.Context = 0
+	leaf.SetMeasureFunc(measureMax)
+	leaf.StyleSetFlexShrink(1)
+	root.InsertChild(leaf, 0)
+
+	sibling := NewNodeWithConfig(root.Config)
+	sibling.StyleSetWidth(30)
+	sibling.StyleSetMinWidth(30)
+	sibling.StyleSetMaxWidth(30)
+	root.InsertChild(sibling, 1)
+
+	root.SetClasses("a")
+	clone := root.Clone(0)
+
+	assert.True(t, clone.Parent == nil)
+	assert.True(t, clone.Config == root.Config)
+	assert.Equal(t, 2, len(clone.Children))
+	assert.True(t, clone.Children[0] == leaf)
+	assert.True(t, leaf.Parent == root)
+	assert.True(t, clone.IsDirty)
+
+	clone.Children[1] = NewNode()
+	clone.AddClass("b")
+	assert.True(t, root.Children[1] != clone.Children[1])
+	assert.Equal(t, []string{"a"}, root.Classes())
+}
+
+func TestCloneRecursive_rewires_parents(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidth(100)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+
+	leaf := NewNodeWithConfig(root.Config)
+	leaf.Context = 0
+	leaf.SetMeasureFunc(measureMax)
+	leaf.StyleSetFlexShrink(1)
+	root.InsertChild(leaf, 0)
+
+	sibling := NewNodeWithConfig(root.Config)
+	sibling.StyleSetWidth(30)
+	sibling.StyleSetMinWidth(30)
+	sibling.StyleSetMaxWidth(30)
+	root.InsertChild(sibling, 1)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	clone := root.CloneRecursive(0)
+	cloneLeaf, cloneSibling := clone.Children[0], clone.Children[1]
+	assert.True(t, cloneLeaf != leaf)
+	assert.True(t, cloneLeaf.Parent == clone)
+	assert.True(t, cloneSibling.Parent == clone)
+	assert.True(t, cloneLeaf.Measure != nil)
+	assert.True(t, FloatIsUndefined(cloneLeaf.LayoutGetWidth()))
+	assert.True(t, cloneSibling.resolvedDimensions[DimensionWidth] == &cloneSibling.Style.MaxDimensions[DimensionWidth])
+
+	cloneSibling.StyleSetWidth(50)
+	cloneSibling.StyleSetMinWidth(50)
+	cloneSibling.StyleSetMaxWidth(50)
+	CalculateLayout(clone, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 50, cloneLeaf.LayoutGetWidth())
+	assertFloatEqual(t, 50, cloneSibling.LayoutGetLeft())
+
+	assert.False(t, root.IsDirty)
+	assertFloatEqual(t, 70, leaf.LayoutGetWidth())
+	assertFloatEqual(t, 30, root.Children[1].LayoutGetWidth())
+}
+
+func TestCloneRecursive_keep_layout(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidth(100)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+
+	leaf := NewNodeWithConfig(root.Config)
+	leaf.Context = 0
+	leaf.SetMeasureFunc(measureMax)
+	leaf.StyleSetFlexShrink(1)
+	root.InsertChild(leaf, 0)
+
+	sibling := NewNodeWithConfig(root.Config)
+	sibling.StyleSetWidth(30)
+	sibling.StyleSetMinWidth(30)
+	sibling.StyleSetMaxWidth(30)
+	root.InsertChild(sibling, 1)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	kept := root.CloneRecursive(CloneOptionsKeepLayout)
+	assert.False(t, kept.IsDirty)
+	assertFloatEqual(t, 70, kept.Children[0].LayoutGetWidth())
+	kept.Children[0].Context = 0
+	CalculateLayout(kept, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, kept.Children[0].Context)
+
+	fresh := root.CloneRecursive(0)
+	fresh.Children[0].Context = 0
+	CalculateLayout(fresh, Undefined, Undefined, DirectionLTR)
+	assert.True(t, fresh.Children[0].Context.(int) > 0)
+	assertFloatEqual(t, 70, fresh.Children[0].LayoutGetWidth())
+}
+
+func TestClone_keep_layout_intrinsic_size(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidthMaxContent()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+
+	rootChild0 := NewNodeWithConfig(root.Config)
+	rootChild0.StyleSetWidth(30)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// the resolved width of the clone is its own intrinsic width
+	clone := root.CloneRecursive(CloneOptionsKeepLayout)
+	rootChild0.StyleSetWidth(50)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 50, root.LayoutGetWidth())
+	assert.True(t, clone.resolvedDimensions[DimensionWidth] == &clone.intrinsicDimensions[DimensionWidth])
+	assertFloatEqual(t, 30, clone.resolvedDimensions[DimensionWidth].Value)
+}
+
+func readLayouts(node *Node) float32 {
+	sum := node.Layout.Position[EdgeLeft] + node.Layout.Dimensions[DimensionWidth]
+	if node.IsDirty {
+		sum++
+	}
+	for _, child := range node.Children {
+		sum += readLayouts(child)
+	}
+	return sum
+}
+
+func TestCopyOnWrite_layout_clones_only_changed_path(t *testing.T) {
+	config := NewConfig()
+	config.CopyOnWrite = true
+	old := NewNodeWithConfig(config)
+	old.StyleSetFlexDirection(FlexDirectionRow)
+	old.StyleSetWidth(300)
+	old.StyleSetHeight(100)
+	for i := 0; i < 3; i++ {
+		column := NewNodeWithConfig(config)
+		column.StyleSetWidth(100)
+		for j := 0; j < 2; j++ {
+			row := NewNodeWithConfig(config)
+			row.StyleSetHeight(20)
+			column.InsertChild(row, j)
+		}
+		old.InsertChild(column, i)
+	}
+	CalculateLayout(old, Undefined, Undefined, DirectionLTR)
+
+	snapshot := old.Clone(CloneOptionsKeepLayout)
+	assert.False(t, snapshot.IsDirty)
+
+	changed := snapshot.MutableChild(1).MutableChild(0)
+	changed.StyleSetHeight(40)
+	assert.True(t, snapshot.IsDirty)
+	assert.False(t, old.IsDirty)
+	assert.False(t, old.Children[1].IsDirty)
+	assert.False(t, old.Children[1].Children[0].IsDirty)
+
+	// readers keep using the old tree while the snapshot is laid out
+	before := readLayouts(old)
+	done := make(chan float32)
+	go func() {
+		done <- readLayouts(old)
+	}()
+	CalculateLayout(snapshot, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, before, <-done)
+	assertFloatEqual(t, before, readLayouts(old))
+
+	// unchanged columns are cloned to be positioned, their rows are shared
+	assert.True(t, snapshot.Children[0].Parent == snapshot)
+	assert.True(t, snapshot.Children[0] != old.Children[0])
+	assert.True(t, snapshot.Children[0].Children[0] == old.Children[0].Children[0])
+	assert.True(t, snapshot.Children[2].Children[1] == old.Children[2].Children[1])
+	assert.True(t, snapshot.Children[1].Children[1] != old.Children[1].Children[1])
+	assertFloatEqual(t, 40, changed.LayoutGetHeight())
+	assertFloatEqual(t, 40, snapshot.Children[1].Children[1].LayoutGetTop())
+	assertFloatEqual(t, 200, snapshot.Children[2].LayoutGetLeft())
+	assertFloatEqual(t, 20, old.Children[1].Children[0].LayoutGetHeight())
+	assertFloatEqual(t, 20, old.Children[1].Children[1].LayoutGetTop())
+	assert.Equal(t, nil, ValidateTree(snapshot))
+}
+
+func TestCopyOnWrite_clone_node_func(t *testing.T) {
+	config := NewConfig()
+	config.CopyOnWrite = true
+	old := NewNodeWithConfig(config)
+	old.StyleSetFlexDirection(FlexDirectionRow)
+	old.StyleSetWidth(300)
+	old.StyleSetHeight(100)
+	for i := 0; i < 3; i++ {
+		column := NewNodeWithConfig(config)
+		column.StyleSetWidth(100)
+		for j := 0; j < 2; j++ {
+			row := NewNodeWithConfig(config)
+			row.StyleSetHeight(20)
+			column.InsertChild(row, j)
+		}
+		old.InsertChild(column, i)
+	}
+	CalculateLayout(old, Undefined, Undefined, DirectionLTR)
+
+	var owners []*Node
+	var indexes []int
+	old.Config.CloneNodeFunc = func(oldNode *Node, owner *Node, childIndex int) *Node {
+		owners = append(owners, owner)
+		indexes = append(indexes, childIndex)
+		return oldNode.Clone(CloneOptionsKeepLayout)
+	}
+	snapshot := old.Clone(CloneOptionsKeepLayout)
+	snapshot.MutableChild(2)
+	assert.Equal(t, []int{2}, indexes)
+	assert.True(t, owners[0] == snapshot)
+
+	shared := snapshot.Children[0]
+	snapshot.RemoveChild(shared)
+	assert.True(t, shared.Parent == old)
+	assert.Equal(t, 2, len(snapshot.Children))
+	assert.Equal(t, 3, len(old.Children))
+}
+
+func TestCopyOnWrite_inherited_style_change(t *testing.T) {
+	config := NewConfig()
+	config.CopyOnWrite = true
+	old := NewNodeWithConfig(config)
+	old.StyleSetFlexDirection(FlexDirectionRow)
+	old.StyleSetWidth(300)
+	old.StyleSetHeight(100)
+	for i := 0; i < 3; i++ {
+		column := NewNodeWithConfig(config)
+		column.StyleSetWidth(100)
+		for j := 0; j < 2; j++ {
+			row := NewNodeWithConfig(config)
+			row.StyleSetHeight(20)
+			column.InsertChild(row, j)
+		}
+		old.InsertChild(column, i)
+	}
+	CalculateLayout(old, Undefined, Undefined, DirectionLTR)
+
+	before := readLayouts(old)
+	snapshot := old.Clone(CloneOptionsKeepLayout)
+	snapshot.StyleSetFontSize(20)
+	assert.True(t, snapshot.Children[2].Children[1].IsDirty)
+	assert.True(t, snapshot.Children[2].Children[1].Parent == snapshot.Children[2])
+	assertFloatEqual(t, before, readLayouts(old))
+}
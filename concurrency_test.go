@@ -0,0 +1,47 @@
+package flex
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrent_layout_of_independent_trees(t *testing.T) {
+	const treeCount = 16
+	config := NewConfig()
+
+	var wg sync.WaitGroup
+	roots := make([]*Node, treeCount)
+	for i := range roots {
+		root := NewNodeWithConfig(config)
+		root.StyleSetFlexDirection(FlexDirectionRow)
+		root.StyleSetFlexWrap(WrapWrap)
+		root.StyleSetWidth(200)
+		for j := 0; j < 20; j++ {
+			child := NewNodeWithConfig(config)
+			child.StyleSetWidth(30)
+			child.StyleSetHeight(10)
+			child.StyleSetFlexGrow(1)
+			root.InsertChild(child, j)
+		}
+		roots[i] = root
+	}
+	for _, root := range roots {
+		wg.Add(1)
+		go func(root *Node) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				root.GetChild(0).StyleSetWidth(float32(30 + i))
+				CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+			}
+		}(root)
+	}
+	wg.Wait()
+
+	for _, root := range roots {
+		assertFloatEqual(t, 200, root.LayoutGetWidth())
+		assertFloatEqual(t, 40, root.LayoutGetHeight())
+		assertFloatEqual(t, 0, root.GetChild(0).LayoutGetLeft())
+		assertFloatEqual(t, 0, root.GetChild(0).LayoutGetTop())
+		assertFloatEqual(t, 10, root.GetChild(19).LayoutGetHeight())
+	}
+}
@@ -0,0 +1,448 @@
+package flex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cssError is an error at a byte offset in the parsed text
+type cssError struct {
+	offset int
+	msg    string
+}
+
+func (e *cssError) Error() string {
+	return e.msg
+}
+
+// toSyntaxError converts err returned by cssParser to *SyntaxError in text
+func toSyntaxError(text string, err error) error {
+	if e, ok := err.(*cssError); ok {
+		return newSyntaxError(text, e.offset, "%s", e.msg)
+	}
+	return err
+}
+
+// toSyntaxErrors converts errors returned by cssParser to SyntaxErrors
+func toSyntaxErrors(text string, errs []error) SyntaxErrors {
+	syntaxErrs := make(SyntaxErrors, len(errs))
+	for i, err := range errs {
+		syntaxErrs[i] = toSyntaxError(text, err).(*SyntaxError)
+	}
+	return syntaxErrs
+}
+
+// cssParser parses CSS values in src[pos:end]
+type cssParser struct {
+	src string
+	pos int
+	end int
+}
+
+func (p *cssParser) errorf(offset int, format string, args ...interface{}) error {
+	return &cssError{offset: offset, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *cssParser) eof() bool {
+	return p.pos >= p.end
+}
+
+func (p *cssParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipSpace skips spaces and comments
+func (p *cssParser) skipSpace() {
+	for !p.eof() {
+		if isCSSSpace(p.src[p.pos]) {
+			p.pos++
+			continue
+		}
+		if !strings.HasPrefix(p.src[p.pos:p.end], "/*") {
+			return
+		}
+		end := strings.Index(p.src[p.pos+2:p.end], "*/")
+		if end < 0 {
+			p.pos = p.end
+			return
+		}
+		p.pos += 2 + end + 2
+	}
+}
+
+// expect skips spaces and c
+func (p *cssParser) expect(c byte) error {
+	p.skipSpace()
+	if p.peek() != c {
+		return p.unexpected(fmt.Sprintf("expected '%c'", c))
+	}
+	p.pos++
+	return nil
+}
+
+// unexpected returns an error describing the text at the current position
+func (p *cssParser) unexpected(what string) error {
+	if p.eof() {
+		return p.errorf(p.pos, "%s, got end of value", what)
+	}
+	start := p.pos
+	end := start + 1
+	for end < p.end && !isCSSSpace(p.src[end]) && p.src[end] != ',' && p.src[end] != ')' {
+		end++
+	}
+	return p.errorf(start, "%s, got %q", what, p.src[start:end])
+}
+
+// done returns an error if there is more than spaces left
+func (p *cssParser) done() error {
+	p.skipSpace()
+	if !p.eof() {
+		return p.unexpected("unexpected value")
+	}
+	return nil
+}
+
+func isCSSSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isCSSIdentStart(c byte) bool {
+	return c == '-' || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isCSSIdentChar(c byte) bool {
+	return isCSSIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// startsNumber returns true if a number starts at the current position
+func (p *cssParser) startsNumber() bool {
+	i := p.pos
+	if i < p.end && (p.src[i] == '+' || p.src[i] == '-') {
+		i++
+	}
+	if i < p.end && p.src[i] == '.' {
+		i++
+	}
+	return i < p.end && isDigit(p.src[i])
+}
+
+// ident returns an identifier at the current position or ""
+func (p *cssParser) ident() string {
+	start := p.pos
+	if p.eof() || !isCSSIdentStart(p.src[p.pos]) {
+		return ""
+	}
+	for !p.eof() && isCSSIdentChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// number parses a number without unit
+func (p *cssParser) number() (float32, error) {
+	p.skipSpace()
+	if !p.startsNumber() {
+		return 0, p.unexpected("expected number")
+	}
+	start := p.pos
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+	}
+	for !p.eof() && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+		p.pos++
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		i := p.pos + 1
+		if i < p.end && (p.src[i] == '+' || p.src[i] == '-') {
+			i++
+		}
+		if i < p.end && isDigit(p.src[i]) {
+			p.pos = i
+			for !p.eof() && isDigit(p.src[p.pos]) {
+				p.pos++
+			}
+		}
+	}
+	f, err := strconv.ParseFloat(p.src[start:p.pos], 32)
+	if err != nil {
+		return 0, p.errorf(start, "invalid number %q", p.src[start:p.pos])
+	}
+	return float32(f), nil
+}
+
+// dimension parses a number with an optional unit. Numbers without unit
+// are points.
+func (p *cssParser) dimension() (Value, error) {
+	f, err := p.number()
+	if err != nil {
+		return ValueUndefined, err
+	}
+	if p.peek() == '%' {
+		p.pos++
+		return Value{Value: f, Unit: UnitPercent}, nil
+	}
+	unitStart := p.pos
+	unit := p.ident()
+	switch unit {
+	case "", "px":
+		return Value{Value: f, Unit: UnitPoint}, nil
+	}
+	for u := UnitVw; u <= UnitRem; u++ {
+		if unit == UnitToString(u) {
+			return Value{Value: f, Unit: u}, nil
+		}
+	}
+	return ValueUndefined, p.errorf(unitStart, "unknown unit %q", unit)
+}
+
+// value parses a length, percentage, keyword or math function
+func (p *cssParser) value() (Value, error) {
+	p.skipSpace()
+	if p.startsNumber() {
+		return p.dimension()
+	}
+	start := p.pos
+	name := p.ident()
+	if p.peek() == '(' {
+		p.pos++
+		switch name {
+		case "fit-content":
+			limit, err := p.dimension()
+			if err != nil {
+				return ValueUndefined, err
+			}
+			if limit.Unit != UnitPoint {
+				return ValueUndefined, p.errorf(start, "fit-content() limit must be in px")
+			}
+			if err := p.expect(')'); err != nil {
+				return ValueUndefined, err
+			}
+			return Value{Value: limit.Value, Unit: UnitFitContent}, nil
+		case "calc", "min", "max", "clamp":
+			calc, err := p.calcFunction(start, name)
+			if err != nil {
+				return ValueUndefined, err
+			}
+			return ValueCalc(calc), nil
+		case "var":
+			calc, err := p.varFunction()
+			if err != nil {
+				return ValueUndefined, err
+			}
+			return ValueCalc(calc), nil
+		}
+		return ValueUndefined, p.errorf(start, "unknown function %q", name)
+	}
+	switch name {
+	case "auto":
+		return ValueAuto, nil
+	case "min-content":
+		return Value{Value: Undefined, Unit: UnitMinContent}, nil
+	case "max-content":
+		return Value{Value: Undefined, Unit: UnitMaxContent}, nil
+	case "fit-content":
+		return Value{Value: Undefined, Unit: UnitFitContent}, nil
+	}
+	p.pos = start
+	return ValueUndefined, p.unexpected("expected value")
+}
+
+// calcFunction parses the arguments of a math function after '('
+func (p *cssParser) calcFunction(start int, name string) (*Calc, error) {
+	if name == "calc" {
+		calc, err := p.calcSum()
+		if err != nil {
+			return nil, err
+		}
+		return calc, p.expect(')')
+	}
+
+	var args []*Calc
+	for {
+		arg, err := p.calcSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		p.skipSpace()
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "min":
+		return CalcMin(args...), nil
+	case "max":
+		return CalcMax(args...), nil
+	}
+	if len(args) != 3 {
+		return nil, p.errorf(start, "clamp() needs 3 arguments, got %d", len(args))
+	}
+	return CalcClamp(args[0], args[1], args[2]), nil
+}
+
+// varFunction parses "--name" or "--name, fallback" after "var("
+func (p *cssParser) varFunction() (*Calc, error) {
+	p.skipSpace()
+	nameStart := p.pos
+	name := p.ident()
+	if !isCustomPropertyName(name) {
+		p.pos = nameStart
+		return nil, p.unexpected("expected custom property name")
+	}
+	var fallback *Calc
+	p.skipSpace()
+	if p.peek() == ',' {
+		p.pos++
+		var err error
+		if fallback, err = p.calcSum(); err != nil {
+			return nil, err
+		}
+	}
+	return CalcVar(name, fallback), p.expect(')')
+}
+
+// calcSum parses "a + b - c"
+func (p *cssParser) calcSum() (*Calc, error) {
+	calc, err := p.calcProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return calc, nil
+		}
+		p.pos++
+		rhs, err := p.calcProduct()
+		if err != nil {
+			return nil, err
+		}
+		if op == '+' {
+			calc = CalcAdd(calc, rhs)
+		} else {
+			calc = CalcSub(calc, rhs)
+		}
+	}
+}
+
+// calcProduct parses "a * 2 / 3" where one side of '*' is a number
+func (p *cssParser) calcProduct() (*Calc, error) {
+	start := p.pos
+	calc, factor, err := p.calcOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		op := p.peek()
+		if op != '*' && op != '/' {
+			break
+		}
+		p.pos++
+		rhsStart := p.pos
+		rhs, rhsFactor, err := p.calcOperand()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case op == '/' && rhs != nil:
+			return nil, p.errorf(rhsStart, "divisor must be a number")
+		case op == '/' && rhsFactor == 0:
+			return nil, p.errorf(rhsStart, "division by zero")
+		case calc != nil && rhs != nil:
+			return nil, p.errorf(rhsStart, "one side of '*' must be a number")
+		case calc == nil && rhs == nil:
+			if op == '*' {
+				factor *= rhsFactor
+			} else {
+				factor /= rhsFactor
+			}
+		case calc == nil:
+			calc = CalcMul(rhs, factor)
+		case op == '*':
+			calc = CalcMul(calc, rhsFactor)
+		default:
+			calc = CalcDiv(calc, rhsFactor)
+		}
+	}
+	if calc == nil {
+		return nil, p.errorf(start, "number must have a unit")
+	}
+	return calc, nil
+}
+
+// calcOperand parses a value, a number or a parenthesized expression. For a
+// number without unit calc is nil.
+func (p *cssParser) calcOperand() (*Calc, float32, error) {
+	p.skipSpace()
+	if p.startsNumber() {
+		start := p.pos
+		f, err := p.number()
+		if err != nil {
+			return nil, 0, err
+		}
+		if p.peek() != '%' && !isCSSIdentStart(p.peek()) {
+			return nil, f, nil
+		}
+		p.pos = start
+		value, err := p.dimension()
+		if err != nil {
+			return nil, 0, err
+		}
+		return CalcValue(value), 0, nil
+	}
+	if p.peek() == '(' {
+		p.pos++
+		calc, err := p.calcSum()
+		if err != nil {
+			return nil, 0, err
+		}
+		return calc, 0, p.expect(')')
+	}
+	start := p.pos
+	name := p.ident()
+	switch name {
+	case "calc", "min", "max", "clamp":
+		if p.peek() == '(' {
+			p.pos++
+			calc, err := p.calcFunction(start, name)
+			return calc, 0, err
+		}
+	case "var":
+		if p.peek() == '(' {
+			p.pos++
+			calc, err := p.varFunction()
+			return calc, 0, err
+		}
+	}
+	p.pos = start
+	return nil, 0, p.unexpected("expected value")
+}
+
+// parseKeyword parses an identifier which is a name of a value of enum T
+func parseKeyword[T ~int](p *cssParser, toString func(T) string) (T, error) {
+	p.skipSpace()
+	start := p.pos
+	name := p.ident()
+	for v := T(0); toString(v) != "unknown"; v++ {
+		if toString(v) == name {
+			return v, nil
+		}
+	}
+	p.pos = start
+	return 0, p.unexpected("unknown keyword")
+}
@@ -0,0 +1,424 @@
+package flex
+
+import (
+	"strings"
+)
+
+// cssProperty parses the CSS value of a property. It returns a function
+// which applies the value to a node, so an invalid declaration doesn't
+// change the style.
+type cssProperty func(p *cssParser) (func(node *Node), error)
+
+func enumProperty[T ~int](toString func(T) string, set func(node *Node, v T)) cssProperty {
+	return func(p *cssParser) (func(node *Node), error) {
+		v, err := parseKeyword(p, toString)
+		if err != nil {
+			return nil, err
+		}
+		return func(node *Node) { set(node, v) }, nil
+	}
+}
+
+func numberProperty(set func(node *Node, f float32)) cssProperty {
+	return func(p *cssParser) (func(node *Node), error) {
+		f, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		return func(node *Node) { set(node, f) }, nil
+	}
+}
+
+func valueProperty(set func(node *Node, v Value)) cssProperty {
+	return func(p *cssParser) (func(node *Node), error) {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return func(node *Node) { set(node, v) }, nil
+	}
+}
+
+func edgeValueProperty(edge Edge, set func(node *Node, edge Edge, v Value)) cssProperty {
+	return valueProperty(func(node *Node, v Value) {
+		set(node, edge, v)
+	})
+}
+
+// borderWidth parses a border width, which must be in points
+func (p *cssParser) borderWidth() (Value, error) {
+	p.skipSpace()
+	start := p.pos
+	v, err := p.dimension()
+	if err != nil {
+		return ValueUndefined, err
+	}
+	if v.Unit != UnitPoint {
+		return ValueUndefined, p.errorf(start, "border must be in px")
+	}
+	return v, nil
+}
+
+func setBorderValue(node *Node, edge Edge, v Value) {
+	node.StyleSetBorder(edge, v.Value)
+}
+
+func borderProperty(edge Edge) cssProperty {
+	return func(p *cssParser) (func(node *Node), error) {
+		v, err := p.borderWidth()
+		if err != nil {
+			return nil, err
+		}
+		return func(node *Node) { setBorderValue(node, edge, v) }, nil
+	}
+}
+
+// wrapKeyword parses a Wrap value, including CSS "nowrap"
+func wrapKeyword(p *cssParser) (Wrap, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.ident() == "nowrap" {
+		return WrapNoWrap, nil
+	}
+	p.pos = start
+	return parseKeyword(p, WrapToString)
+}
+
+func flexWrapProperty(p *cssParser) (func(node *Node), error) {
+	wrap, err := wrapKeyword(p)
+	if err != nil {
+		return nil, err
+	}
+	return func(node *Node) { node.StyleSetFlexWrap(wrap) }, nil
+}
+
+// more returns true if there is more than spaces left
+func (p *cssParser) more() bool {
+	p.skipSpace()
+	return !p.eof()
+}
+
+// unitlessNumber parses a number if it has no unit
+func (p *cssParser) unitlessNumber() (float32, bool) {
+	p.skipSpace()
+	if !p.startsNumber() {
+		return 0, false
+	}
+	start := p.pos
+	f, err := p.number()
+	if err != nil || p.peek() == '%' || isCSSIdentStart(p.peek()) {
+		p.pos = start
+		return 0, false
+	}
+	return f, true
+}
+
+// edgesShorthand parses 1 to 4 values for the top, right, bottom and left
+// edges. A single value sets EdgeAll. Other edges are reset, like CSS
+// shorthands reset their longhands.
+func edgesShorthand(parse func(p *cssParser) (Value, error), set func(node *Node, edge Edge, v Value)) cssProperty {
+	return func(p *cssParser) (func(node *Node), error) {
+		var values []Value
+		for len(values) < 4 && p.more() {
+			v, err := parse(p)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+
+		var edges [EdgeCount]Value
+		for i := range edges {
+			edges[i] = ValueUndefined
+		}
+		switch len(values) {
+		case 1:
+			edges[EdgeAll] = values[0]
+		case 2:
+			edges[EdgeTop], edges[EdgeBottom] = values[0], values[0]
+			edges[EdgeRight], edges[EdgeLeft] = values[1], values[1]
+		case 3:
+			edges[EdgeTop], edges[EdgeBottom] = values[0], values[2]
+			edges[EdgeRight], edges[EdgeLeft] = values[1], values[1]
+		case 4:
+			edges[EdgeTop], edges[EdgeRight] = values[0], values[1]
+			edges[EdgeBottom], edges[EdgeLeft] = values[2], values[3]
+		}
+		return func(node *Node) {
+			for edge := EdgeLeft; edge < EdgeCount; edge++ {
+				set(node, edge, edges[edge])
+			}
+		}, nil
+	}
+}
+
+// flexShorthand parses "flex". Grow, shrink and basis are set, with CSS
+// defaults for omitted values, so "flex: 2" is "flex: 2 1 0%".
+func flexShorthand(p *cssParser) (func(node *Node), error) {
+	p.skipSpace()
+	start := p.pos
+	grow, shrink, basis := float32(1), float32(1), Value{Value: 0, Unit: UnitPercent}
+	switch p.ident() {
+	case "none":
+		grow, shrink, basis = 0, 0, ValueAuto
+	case "auto":
+		basis = ValueAuto
+	case "initial":
+		grow, basis = 0, ValueAuto
+	default:
+		p.pos = start
+		n := 0
+		hasBasis := false
+		for p.more() {
+			if f, ok := p.unitlessNumber(); ok && n < 2 && !hasBasis {
+				if n == 0 {
+					grow = f
+				} else {
+					shrink = f
+				}
+				n++
+				continue
+			}
+			if hasBasis {
+				return nil, p.unexpected("unexpected value")
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			basis = v
+			hasBasis = true
+		}
+	}
+	return func(node *Node) {
+		node.StyleSetFlex(Undefined)
+		node.StyleSetFlexGrow(grow)
+		node.StyleSetFlexShrink(shrink)
+		node.StyleSetFlexBasisValue(basis)
+	}, nil
+}
+
+// flexFlowShorthand parses "flex-flow: <direction> <wrap>" in any order
+func flexFlowShorthand(p *cssParser) (func(node *Node), error) {
+	var direction *FlexDirection
+	var wrap *Wrap
+	for p.more() {
+		start := p.pos
+		if direction == nil {
+			if v, err := parseKeyword(p, FlexDirectionToString); err == nil {
+				direction = &v
+				continue
+			}
+		}
+		if wrap == nil {
+			if v, err := wrapKeyword(p); err == nil {
+				wrap = &v
+				continue
+			}
+		}
+		p.pos = start
+		return nil, p.unexpected("unknown keyword")
+	}
+	return func(node *Node) {
+		flexDirection := FlexDirectionColumn
+		if node.Config != nil && node.Config.UseWebDefaults {
+			flexDirection = FlexDirectionRow
+		}
+		if direction != nil {
+			flexDirection = *direction
+		}
+		flexWrap := WrapNoWrap
+		if wrap != nil {
+			flexWrap = *wrap
+		}
+		node.StyleSetFlexDirection(flexDirection)
+		node.StyleSetFlexWrap(flexWrap)
+	}, nil
+}
+
+// placeContentShorthand parses "place-content: <align-content> <justify-content>".
+// A single value sets both. Like in CSS, a single value which isn't a
+// justify-content, such as stretch or baseline, sets justify-content to
+// flex-start.
+func placeContentShorthand(p *cssParser) (func(node *Node), error) {
+	p.skipSpace()
+	start := p.pos
+	align, err := parseKeyword(p, AlignToString)
+	if err != nil {
+		return nil, err
+	}
+	var justify Justify
+	if p.more() {
+		justify, err = parseKeyword(p, JustifyToString)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		end := p.pos
+		p.pos = start
+		justify, err = parseKeyword(p, JustifyToString)
+		if err != nil {
+			justify = JustifyFlexStart
+			p.pos = end
+		}
+	}
+	return func(node *Node) {
+		node.StyleSetAlignContent(align)
+		node.StyleSetJustifyContent(justify)
+	}, nil
+}
+
+// cssProperties are the supported CSS properties. Longhands are named like
+// in NodePrint.
+var cssProperties = map[string]cssProperty{
+	"direction":       enumProperty(DirectionToString, (*Node).StyleSetDirection),
+	"flex-direction":  enumProperty(FlexDirectionToString, (*Node).StyleSetFlexDirection),
+	"justify-content": enumProperty(JustifyToString, (*Node).StyleSetJustifyContent),
+	"align-items":     enumProperty(AlignToString, (*Node).StyleSetAlignItems),
+	"align-content":   enumProperty(AlignToString, (*Node).StyleSetAlignContent),
+	"align-self":      enumProperty(AlignToString, (*Node).StyleSetAlignSelf),
+	"flex-wrap":       flexWrapProperty,
+	"overflow":        enumProperty(OverflowToString, (*Node).StyleSetOverflow),
+	"display":         enumProperty(DisplayToString, (*Node).StyleSetDisplay),
+	"pointer-events":  enumProperty(PointerEventsToString, (*Node).StyleSetPointerEvents),
+	"box-sizing":      enumProperty(BoxSizingToString, (*Node).StyleSetBoxSizing),
+	"position":        enumProperty(PositionTypeToString, (*Node).StyleSetPositionType),
+
+	"flex-grow":    numberProperty((*Node).StyleSetFlexGrow),
+	"flex-shrink":  numberProperty((*Node).StyleSetFlexShrink),
+	"aspect-ratio": numberProperty((*Node).StyleSetAspectRatio),
+
+	"flex-basis": valueProperty((*Node).StyleSetFlexBasisValue),
+	"width":      valueProperty((*Node).StyleSetWidthValue),
+	"height":     valueProperty((*Node).StyleSetHeightValue),
+	"min-width":  valueProperty((*Node).StyleSetMinWidthValue),
+	"min-height": valueProperty((*Node).StyleSetMinHeightValue),
+	"max-width":  valueProperty((*Node).StyleSetMaxWidthValue),
+	"max-height": valueProperty((*Node).StyleSetMaxHeightValue),
+	"font-size":  valueProperty((*Node).StyleSetFontSizeValue),
+	"gap": valueProperty(func(node *Node, v Value) {
+		node.StyleSetGapValue(GutterAll, v)
+	}),
+	"row-gap": valueProperty(func(node *Node, v Value) {
+		node.StyleSetGapValue(GutterRow, v)
+	}),
+	"column-gap": valueProperty(func(node *Node, v Value) {
+		node.StyleSetGapValue(GutterColumn, v)
+	}),
+
+	"flex":          flexShorthand,
+	"flex-flow":     flexFlowShorthand,
+	"place-content": placeContentShorthand,
+	"margin":        edgesShorthand((*cssParser).value, (*Node).StyleSetMarginValue),
+	"padding":       edgesShorthand((*cssParser).value, (*Node).StyleSetPaddingValue),
+	"border-width":  edgesShorthand((*cssParser).borderWidth, setBorderValue),
+	"inset":         edgesShorthand((*cssParser).value, (*Node).StyleSetPositionValue),
+}
+
+func init() {
+	for edge := EdgeLeft; edge < EdgeAll; edge++ {
+		suffix := "-" + EdgeToString(edge)
+		cssProperties["margin"+suffix] = edgeValueProperty(edge, (*Node).StyleSetMarginValue)
+		cssProperties["padding"+suffix] = edgeValueProperty(edge, (*Node).StyleSetPaddingValue)
+		cssProperties["border"+suffix] = borderProperty(edge)
+		cssProperties["border"+suffix+"-width"] = borderProperty(edge)
+	}
+	// NodePrint writes border on all edges as "border"
+	cssProperties["border"] = borderProperty(EdgeAll)
+	for _, edge := range []Edge{EdgeLeft, EdgeTop, EdgeRight, EdgeBottom} {
+		cssProperties[EdgeToString(edge)] = edgeValueProperty(edge, (*Node).StyleSetPositionValue)
+	}
+	cssProperties["inset-inline-start"] = edgeValueProperty(EdgeStart, (*Node).StyleSetPositionValue)
+	cssProperties["inset-inline-end"] = edgeValueProperty(EdgeEnd, (*Node).StyleSetPositionValue)
+}
+
+// parseDeclarations applies "name: value; ..." in src[start:end] to node.
+// Invalid declarations are skipped and returned as *cssError with an offset
+// in src. Names in aliases are looked up before cssProperties.
+func parseDeclarations(node *Node, aliases map[string]cssProperty, src string, start int, end int) []error {
+	decls, errs := parseDeclarationList(aliases, src, start, end)
+	for _, apply := range decls {
+		apply(node)
+	}
+	return errs
+}
+
+// parseDeclarationList parses "name: value; ..." in src[start:end] into
+// functions which apply the valid declarations
+func parseDeclarationList(aliases map[string]cssProperty, src string, start int, end int) ([]func(node *Node), []error) {
+	var decls []func(node *Node)
+	var errs []error
+	p := &cssParser{src: src, pos: start, end: end}
+	for {
+		p.skipSpace()
+		for p.peek() == ';' {
+			p.pos++
+			p.skipSpace()
+		}
+		if p.eof() {
+			return decls, errs
+		}
+
+		declEnd := strings.IndexByte(src[p.pos:end], ';')
+		if declEnd < 0 {
+			declEnd = end
+		} else {
+			declEnd += p.pos
+		}
+		apply, err := parseDeclaration(aliases, p, declEnd)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			decls = append(decls, apply)
+		}
+		p.pos = declEnd
+	}
+}
+
+// parseDeclaration parses a single "name: value" ending at end
+func parseDeclaration(aliases map[string]cssProperty, p *cssParser, end int) (func(node *Node), error) {
+	p = &cssParser{src: p.src, pos: p.pos, end: end}
+	nameStart := p.pos
+	name := p.ident()
+	if name == "" {
+		return nil, p.unexpected("expected property name")
+	}
+	if err := p.expect(':'); err != nil {
+		return nil, err
+	}
+
+	property := aliases[name]
+	if property == nil {
+		property = cssProperties[name]
+	}
+	if isCustomPropertyName(name) {
+		property = valueProperty(func(node *Node, v Value) {
+			node.StyleSetCustomProperty(name, v)
+		})
+	}
+	if property == nil {
+		return nil, p.errorf(nameStart, "unknown property %q", name)
+	}
+	if !p.more() {
+		return nil, p.errorf(nameStart, "missing value of %q", name)
+	}
+	apply, err := property(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.done(); err != nil {
+		return nil, err
+	}
+	return apply, nil
+}
+
+// StyleSetCSS applies a CSS declaration block, like
+// "flex: 1 1 auto; margin: 4px 8px", to the style of node. Invalid
+// declarations are skipped and returned as SyntaxErrors.
+func (node *Node) StyleSetCSS(css string) error {
+	errs := parseDeclarations(node, nil, css, 0, len(css))
+	if len(errs) == 0 {
+		return nil
+	}
+	return toSyntaxErrors(css, errs)
+}
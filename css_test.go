@@ -0,0 +1,190 @@
+package flex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyleSetCSS_longhands(t *testing.T) {
+	node := NewNode()
+	err := node.StyleSetCSS(`
+		flex-direction: row-reverse;
+		justify-content: space-evenly;
+		align-items: baseline;
+		position: absolute;
+		flex-wrap: nowrap;
+		width: 50%;
+		height: 10vh;
+		min-width: calc(100% - 2 * 10px);
+		max-width: min(200px, 50em);
+		flex-basis: fit-content(30px);
+		padding-left: 2rem;
+		border-top-width: 3px;
+		top: auto;
+		row-gap: 4px;
+	`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, FlexDirectionRowReverse, node.Style.FlexDirection)
+	assert.Equal(t, JustifySpaceEvenly, node.Style.JustifyContent)
+	assert.Equal(t, AlignBaseline, node.Style.AlignItems)
+	assert.Equal(t, PositionTypeAbsolute, node.Style.PositionType)
+	assert.Equal(t, Value{Value: 50, Unit: UnitPercent}, node.StyleGetWidth())
+	assert.Equal(t, Value{Value: 10, Unit: UnitVh}, node.StyleGetHeight())
+	assert.Equal(t, "calc(100% - (10px * 2))", node.Style.MinDimensions[DimensionWidth].Calc.String())
+	assert.Equal(t, "min(200px, 50em)", node.Style.MaxDimensions[DimensionWidth].Calc.String())
+	assert.Equal(t, Value{Value: 30, Unit: UnitFitContent}, node.Style.FlexBasis)
+	assert.Equal(t, Value{Value: 2, Unit: UnitRem}, node.Style.Padding[EdgeLeft])
+	assertFloatEqual(t, 3, node.StyleGetBorder(EdgeTop))
+	assert.True(t, valueEq(ValueAuto, node.Style.Position[EdgeTop]))
+	assert.Equal(t, Value{Value: 4, Unit: UnitPoint}, node.Style.Gap[GutterRow])
+}
+
+func TestStyleSetCSS_flex(t *testing.T) {
+	tests := []struct {
+		css    string
+		flex   float32
+		grow   float32
+		shrink float32
+		basis  Value
+	}{
+		{"flex: 2", Undefined, 2, 1, Value{Value: 0, Unit: UnitPercent}},
+		{"flex: 1 1 auto", Undefined, 1, 1, ValueAuto},
+		{"flex: none", Undefined, 0, 0, ValueAuto},
+		{"flex: auto", Undefined, 1, 1, ValueAuto},
+		{"flex: initial", Undefined, 0, 1, ValueAuto},
+		{"flex: 10px", Undefined, 1, 1, Value{Value: 10, Unit: UnitPoint}},
+		{"flex: 2 3", Undefined, 2, 3, Value{Value: 0, Unit: UnitPercent}},
+		{"flex: 2 30%", Undefined, 2, 1, Value{Value: 30, Unit: UnitPercent}},
+	}
+	for _, test := range tests {
+		node := NewNode()
+		node.StyleSetFlexGrow(5)
+		assert.Equal(t, nil, node.StyleSetCSS(test.css), test.css)
+		assert.True(t, FloatsEqual(test.flex, node.Style.Flex), test.css)
+		assert.True(t, FloatsEqual(test.grow, node.Style.FlexGrow), test.css)
+		assert.True(t, FloatsEqual(test.shrink, node.Style.FlexShrink), test.css)
+		assert.True(t, valueEq(test.basis, node.Style.FlexBasis), test.css)
+	}
+}
+
+func TestStyleSetCSS_edges(t *testing.T) {
+	node := NewNode()
+	assert.Equal(t, nil, node.StyleSetCSS("margin-left: 5px; margin: 4px 8px"))
+	assert.Equal(t, Value{Value: 4, Unit: UnitPoint}, node.Style.Margin[EdgeTop])
+	assert.Equal(t, Value{Value: 8, Unit: UnitPoint}, node.Style.Margin[EdgeRight])
+	assert.Equal(t, Value{Value: 4, Unit: UnitPoint}, node.Style.Margin[EdgeBottom])
+	assert.Equal(t, Value{Value: 8, Unit: UnitPoint}, node.Style.Margin[EdgeLeft])
+	assert.True(t, valueEq(ValueUndefined, node.Style.Margin[EdgeAll]))
+
+	assert.Equal(t, nil, node.StyleSetCSS("margin: auto"))
+	assert.True(t, valueEq(ValueAuto, node.Style.Margin[EdgeAll]))
+	assert.True(t, valueEq(ValueUndefined, node.Style.Margin[EdgeLeft]))
+
+	assert.Equal(t, nil, node.StyleSetCSS("padding: 1px 2px 3px; border-width: 1px 2px 3px 4px; inset: 10% 0"))
+	assert.Equal(t, Value{Value: 1, Unit: UnitPoint}, node.Style.Padding[EdgeTop])
+	assert.Equal(t, Value{Value: 2, Unit: UnitPoint}, node.Style.Padding[EdgeLeft])
+	assert.Equal(t, Value{Value: 3, Unit: UnitPoint}, node.Style.Padding[EdgeBottom])
+	assertFloatEqual(t, 1, node.StyleGetBorder(EdgeTop))
+	assertFloatEqual(t, 2, node.StyleGetBorder(EdgeRight))
+	assertFloatEqual(t, 3, node.StyleGetBorder(EdgeBottom))
+	assertFloatEqual(t, 4, node.StyleGetBorder(EdgeLeft))
+	assert.Equal(t, Value{Value: 10, Unit: UnitPercent}, node.Style.Position[EdgeBottom])
+	assert.Equal(t, Value{Value: 0, Unit: UnitPoint}, node.Style.Position[EdgeRight])
+
+	assert.Equal(t, nil, node.StyleSetCSS("inset-inline-start: 5px; inset-inline-end: 6px"))
+	assert.Equal(t, Value{Value: 5, Unit: UnitPoint}, node.Style.Position[EdgeStart])
+	assert.Equal(t, Value{Value: 6, Unit: UnitPoint}, node.Style.Position[EdgeEnd])
+	for _, css := range []string{"start: 1px", "end: 1px", "horizontal: 1px", "vertical: 1px", "flexWrap: wrap", "margin-all: 1px", "width: undefined"} {
+		assert.True(t, node.StyleSetCSS(css) != nil, css)
+	}
+}
+
+func TestStyleSetCSS_flex_flow_and_place_content(t *testing.T) {
+	node := NewNode()
+	assert.Equal(t, nil, node.StyleSetCSS("flex-flow: wrap-reverse row; place-content: space-between flex-end"))
+	assert.Equal(t, FlexDirectionRow, node.Style.FlexDirection)
+	assert.Equal(t, WrapWrapReverse, node.Style.FlexWrap)
+	assert.Equal(t, AlignSpaceBetween, node.Style.AlignContent)
+	assert.Equal(t, JustifyFlexEnd, node.Style.JustifyContent)
+
+	assert.Equal(t, nil, node.StyleSetCSS("flex-flow: wrap; place-content: center"))
+	assert.Equal(t, FlexDirectionColumn, node.Style.FlexDirection)
+	assert.Equal(t, WrapWrap, node.Style.FlexWrap)
+	assert.Equal(t, AlignCenter, node.Style.AlignContent)
+	assert.Equal(t, JustifyCenter, node.Style.JustifyContent)
+
+	// stretch isn't a justify-content, so it falls back to flex-start
+	node.StyleSetJustifyContent(JustifyCenter)
+	assert.Equal(t, nil, node.StyleSetCSS("place-content: stretch"))
+	assert.Equal(t, AlignStretch, node.Style.AlignContent)
+	assert.Equal(t, JustifyFlexStart, node.Style.JustifyContent)
+}
+
+func TestStyleSetCSS_layout(t *testing.T) {
+	root := NewNode()
+	root.StyleSetCSS("flex-flow: row; width: 200px; height: 100px")
+	child := NewNode()
+	root.InsertChild(child, 0)
+	assert.Equal(t, nil, child.StyleSetCSS("flex: 1 1 auto; margin: 4px 8px; width: 50%"))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 8, child.LayoutGetLeft())
+	assertFloatEqual(t, 4, child.LayoutGetTop())
+	assertFloatEqual(t, 184, child.LayoutGetWidth())
+	assertFloatEqual(t, 92, child.LayoutGetHeight())
+}
+
+func TestStyleSetCSS_errors(t *testing.T) {
+	node := NewNode()
+	err := node.StyleSetCSS("width: 10px; foo: 1;\nheight: abc; margin: 1px 2px 3px 4px 5px;\nplace-content: center auto; flex-grow: 2")
+
+	var syntaxErrs SyntaxErrors
+	assert.True(t, errors.As(err, &syntaxErrs))
+	assert.Equal(t, 4, len(syntaxErrs))
+	exp := []struct {
+		line   int
+		column int
+		msg    string
+	}{
+		{1, 14, `unknown property "foo"`},
+		{2, 9, `expected value, got "abc"`},
+		{2, 38, `unexpected value, got "5px"`},
+		{3, 23, `unknown keyword, got "auto"`},
+	}
+	for i, e := range exp {
+		assert.Equal(t, e.line, syntaxErrs[i].Line)
+		assert.Equal(t, e.column, syntaxErrs[i].Column)
+		assert.Equal(t, e.msg, syntaxErrs[i].Msg)
+	}
+
+	var syntaxErr *SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+
+	// valid declarations are applied, invalid ones don't change the style
+	assert.Equal(t, Value{Value: 10, Unit: UnitPoint}, node.StyleGetWidth())
+	assert.True(t, valueEq(ValueAuto, node.StyleGetHeight()))
+	assert.True(t, valueEq(ValueUndefined, node.Style.Margin[EdgeTop]))
+	assert.Equal(t, AlignFlexStart, node.Style.AlignContent)
+	assertFloatEqual(t, 2, node.Style.FlexGrow)
+}
+
+func TestStyleSetCSS_calc_errors(t *testing.T) {
+	tests := []struct {
+		css string
+		msg string
+	}{
+		{"width: calc(10px * 20px)", `one side of '*' must be a number`},
+		{"width: calc(10px / 0)", "division by zero"},
+		{"width: calc(10px * (2 / 0))", "division by zero"},
+		{"width: calc(10px / 5px)", "divisor must be a number"},
+	}
+	for _, test := range tests {
+		node := NewNode()
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(node.StyleSetCSS(test.css), &syntaxErr), test.css)
+		assert.Equal(t, test.msg, syntaxErr.Msg)
+		assert.True(t, valueEq(ValueAuto, node.StyleGetWidth()), test.css)
+	}
+}
@@ -0,0 +1,228 @@
+package flex
+
+import "strings"
+
+// StyleSetCustomProperty sets the custom property name, like "--spacing",
+// which node and its descendants can use with ValueVar. An undefined value
+// removes the property. Nodes using the property are marked dirty.
+func (node *Node) StyleSetCustomProperty(name string, value Value) {
+	old, ok := node.Style.CustomProperties[name]
+	if !ok && value.Unit == UnitUndefined || ok && valueEq(old, value) {
+		return
+	}
+	properties := make(map[string]Value, len(node.Style.CustomProperties)+1)
+	for k, v := range node.Style.CustomProperties {
+		properties[k] = v
+	}
+	if value.Unit == UnitUndefined {
+		delete(properties, name)
+	} else {
+		properties[name] = value
+	}
+	if len(properties) == 0 {
+		properties = nil
+	}
+	node.Style.CustomProperties = properties
+	nodeClearUnitBases(node)
+	nodeMarkCustomPropertyUsersDirty(node, map[string]bool{name: true})
+}
+
+// StyleGetCustomProperty gets the custom property name set on node, without
+// the ones inherited from ancestors
+func (node *Node) StyleGetCustomProperty(name string) (Value, bool) {
+	value, ok := node.Style.CustomProperties[name]
+	return value, ok
+}
+
+// CustomProperty returns the value of custom property name set on node or
+// inherited from the closest ancestor that sets it
+func (node *Node) CustomProperty(name string) (Value, bool) {
+	for n := node; n != nil; n = n.Parent {
+		if value, ok := n.Style.CustomProperties[name]; ok {
+			return value, true
+		}
+	}
+	return ValueUndefined, false
+}
+
+// resolveCustomProperty resolves var() with the custom properties of bases.
+// Like in CSS the value is resolved where it is used, so em units and
+// percents in it are relative to the node using it. Undefined and cyclic
+// properties use the fallback.
+func resolveCustomProperty(calc *Calc, parentSize float32, bases *unitBases) float32 {
+	value, ok := bases.properties[calc.Name]
+	if !ok || bases.cyclic[calc.Name] {
+		if len(calc.Args) == 0 {
+			return Undefined
+		}
+		return calc.Args[0].resolve(parentSize, bases)
+	}
+	return resolveValueWithBases(&value, parentSize, bases)
+}
+
+// inheritCustomProperties returns the custom properties of a node setting
+// own, with inherited and inheritedCyclic those of its parent, and the
+// cyclic ones of them. Nodes which set none share the maps of the parent.
+func inheritCustomProperties(inherited map[string]Value, inheritedCyclic map[string]bool,
+	own map[string]Value) (map[string]Value, map[string]bool) {
+	if len(own) == 0 {
+		return inherited, inheritedCyclic
+	}
+	properties := make(map[string]Value, len(inherited)+len(own))
+	for name, value := range inherited {
+		properties[name] = value
+	}
+	for name, value := range own {
+		properties[name] = value
+	}
+	// a property set on the node can make inherited ones cyclic, or break
+	// their cycle
+	var cyclic map[string]bool
+	for name := range properties {
+		if customPropertyIsCyclic(properties, name, nil) {
+			if cyclic == nil {
+				cyclic = map[string]bool{}
+			}
+			cyclic[name] = true
+		}
+	}
+	return properties, cyclic
+}
+
+// customPropertyIsCyclic returns true if the value of name in properties
+// depends on itself or on a cyclic property. visiting are the properties
+// whose values are being checked.
+func customPropertyIsCyclic(properties map[string]Value, name string, visiting []string) bool {
+	for _, v := range visiting {
+		if v == name {
+			return true
+		}
+	}
+	value, ok := properties[name]
+	if !ok || value.Unit != UnitCalc || value.Calc == nil {
+		return false
+	}
+	visiting = append(visiting, name)
+	return value.Calc.anyCustomProperty(func(ref string) bool {
+		return customPropertyIsCyclic(properties, ref, visiting)
+	})
+}
+
+// changedCustomProperties returns the names of custom properties that are
+// different in s1 and s2
+func changedCustomProperties(s1, s2 *Style) map[string]bool {
+	var changed map[string]bool
+	add := func(name string) {
+		if changed == nil {
+			changed = map[string]bool{}
+		}
+		changed[name] = true
+	}
+	for name, v1 := range s1.CustomProperties {
+		if v2, ok := s2.CustomProperties[name]; !ok || !valueEq(v1, v2) {
+			add(name)
+		}
+	}
+	for name := range s2.CustomProperties {
+		if _, ok := s1.CustomProperties[name]; !ok {
+			add(name)
+		}
+	}
+	return changed
+}
+
+// nodeMarkCustomPropertyUsersDirty marks node and its descendants dirty if
+// they use one of the changed custom properties in names. Descendants that
+// set a property themselves don't depend on the changed one.
+func nodeMarkCustomPropertyUsersDirty(node *Node, names map[string]bool) {
+	names = customPropertyDependents(&node.Style, names)
+	if valueUsesCustomProperty(&node.Style.FontSize, names) {
+		// em units of descendants depend on the font size
+		nodeMarkDirtyRecursive(node)
+		return
+	}
+	for _, value := range styleValues(&node.Style) {
+		if valueUsesCustomProperty(value, names) {
+			nodeMarkDirtyInternal(node)
+			break
+		}
+	}
+
+	for i := range node.Children {
+		child := nodeChild(node, i)
+		inherited := names
+		for name := range child.Style.CustomProperties {
+			if inherited[name] {
+				inherited = customPropertiesWithout(inherited, child.Style.CustomProperties)
+				break
+			}
+		}
+		if len(inherited) > 0 {
+			nodeMarkCustomPropertyUsersDirty(child, inherited)
+		}
+	}
+}
+
+// customPropertyDependents returns names and the custom properties of style
+// defined with them, like "--b: calc(var(--a) * 2)" for "--a"
+func customPropertyDependents(style *Style, names map[string]bool) map[string]bool {
+	dependents := names
+	copied := false
+	for changed := true; changed; {
+		changed = false
+		for name, value := range style.CustomProperties {
+			if !dependents[name] && valueUsesCustomProperty(&value, dependents) {
+				if !copied {
+					dependents = customPropertiesWithout(names, nil)
+					copied = true
+				}
+				dependents[name] = true
+				changed = true
+			}
+		}
+	}
+	return dependents
+}
+
+// customPropertiesWithout returns a copy of names without the properties
+// set in properties
+func customPropertiesWithout(names map[string]bool, properties map[string]Value) map[string]bool {
+	result := make(map[string]bool, len(names))
+	for name := range names {
+		if _, ok := properties[name]; !ok {
+			result[name] = true
+		}
+	}
+	return result
+}
+
+func valueUsesCustomProperty(value *Value, names map[string]bool) bool {
+	return value.Unit == UnitCalc && value.Calc != nil && value.Calc.usesCustomProperty(names)
+}
+
+// usesCustomProperty returns true if calc uses one of the custom properties
+// in names
+func (calc *Calc) usesCustomProperty(names map[string]bool) bool {
+	return calc.anyCustomProperty(func(name string) bool {
+		return names[name]
+	})
+}
+
+// anyCustomProperty returns true if f returns true for a custom property
+// used by calc
+func (calc *Calc) anyCustomProperty(f func(name string) bool) bool {
+	if calc.Op == CalcOpVar && f(calc.Name) {
+		return true
+	}
+	for _, arg := range calc.Args {
+		if arg.anyCustomProperty(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCustomPropertyName returns true for names like "--spacing"
+func isCustomPropertyName(name string) bool {
+	return len(name) > 2 && strings.HasPrefix(name, "--")
+}
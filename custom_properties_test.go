@@ -0,0 +1,215 @@
+package flex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomProperties_inherited(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidth(200)
+	root.StyleSetCustomProperty("--spacing", Value{Value: 10, Unit: UnitPoint})
+	root.StyleSetCustomProperty("--side", Value{Value: 25, Unit: UnitPercent})
+
+	child := NewNodeWithConfig(root.Config)
+	child.StyleSetPaddingValue(EdgeAll, ValueVar("--spacing"))
+	child.StyleSetWidthValue(ValueVar("--side"))
+	child.StyleSetHeightValue(ValueCalc(CalcMul(CalcVar("--spacing", nil), 3)))
+	child.StyleSetMarginValue(EdgeLeft, ValueCalc(CalcVar("--missing", CalcPoint(7))))
+	root.InsertChild(child, 0)
+
+	grandChild := NewNodeWithConfig(root.Config)
+	grandChild.StyleSetCustomProperty("--spacing", Value{Value: 2, Unit: UnitEm})
+	grandChild.StyleSetFontSize(4)
+	grandChild.StyleSetWidthValue(ValueVar("--spacing"))
+	child.InsertChild(grandChild, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 10, child.LayoutGetPadding(EdgeLeft))
+	assertFloatEqual(t, 50, child.LayoutGetWidth())
+	assertFloatEqual(t, 30, child.LayoutGetHeight())
+	assertFloatEqual(t, 7, child.LayoutGetLeft())
+	assertFloatEqual(t, 8, grandChild.LayoutGetWidth())
+
+	value, ok := grandChild.CustomProperty("--side")
+	assert.True(t, ok)
+	assert.Equal(t, Value{Value: 25, Unit: UnitPercent}, value)
+	_, ok = grandChild.StyleGetCustomProperty("--side")
+	assert.False(t, ok)
+}
+
+func TestCustomProperties_cycle_uses_fallback(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetCustomProperty("--a", ValueVar("--b"))
+	root.StyleSetCustomProperty("--b", ValueVar("--a"))
+	root.StyleSetWidthValue(ValueCalc(CalcVar("--a", CalcPoint(5))))
+	root.StyleSetHeightValue(ValueCalc(CalcVar("--b", nil)))
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 5, root.LayoutGetWidth())
+	assertFloatEqual(t, 0, root.LayoutGetHeight())
+}
+
+func TestCustomProperties_cycle_broken_by_descendant(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetCustomProperty("--a", ValueVar("--b"))
+	root.StyleSetCustomProperty("--b", ValueVar("--a"))
+	root.StyleSetWidthValue(ValueCalc(CalcVar("--a", CalcPoint(5))))
+
+	child := NewNodeWithConfig(root.Config)
+	child.StyleSetCustomProperty("--b", Value{Value: 6, Unit: UnitPoint})
+	child.StyleSetWidthValue(ValueVar("--a"))
+	child.StyleSetHeight(10)
+	root.InsertChild(child, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 5, root.LayoutGetWidth())
+	assertFloatEqual(t, 6, child.LayoutGetWidth())
+}
+
+func TestCustomProperties_change_dirties_only_dependents(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetWidth(300)
+	root.StyleSetCustomProperty("--spacing", Value{Value: 10, Unit: UnitPoint})
+
+	user := NewNodeWithConfig(root.Config)
+	user.StyleSetHeightValue(ValueVar("--spacing"))
+	root.InsertChild(user, 0)
+
+	other := NewNodeWithConfig(root.Config)
+	other.StyleSetHeight(20)
+	root.InsertChild(other, 1)
+
+	shadowing := NewNodeWithConfig(root.Config)
+	shadowing.StyleSetCustomProperty("--spacing", Value{Value: 1, Unit: UnitPoint})
+	shadowing.StyleSetHeightValue(ValueVar("--spacing"))
+	root.InsertChild(shadowing, 2)
+
+	derived := NewNodeWithConfig(root.Config)
+	derived.StyleSetCustomProperty("--double", ValueCalc(CalcMul(CalcVar("--spacing", nil), 2)))
+	root.InsertChild(derived, 3)
+	derivedUser := NewNodeWithConfig(root.Config)
+	derivedUser.StyleSetHeightValue(ValueVar("--double"))
+	derived.InsertChild(derivedUser, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 20, derivedUser.LayoutGetHeight())
+
+	root.StyleSetCustomProperty("--spacing", Value{Value: 15, Unit: UnitPoint})
+	assert.True(t, root.IsDirty)
+	assert.True(t, user.IsDirty)
+	assert.False(t, other.IsDirty)
+	assert.False(t, shadowing.IsDirty)
+	assert.True(t, derived.IsDirty)
+	assert.True(t, derivedUser.IsDirty)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 15, user.LayoutGetHeight())
+	assertFloatEqual(t, 1, shadowing.LayoutGetHeight())
+	assertFloatEqual(t, 30, derivedUser.LayoutGetHeight())
+
+	root.StyleSetCustomProperty("--unused", Value{Value: 1, Unit: UnitPoint})
+	assert.False(t, root.IsDirty)
+	root.StyleSetCustomProperty("--spacing", ValueUndefined)
+	assert.True(t, user.IsDirty)
+	_, ok := root.StyleGetCustomProperty("--spacing")
+	assert.False(t, ok)
+}
+
+func TestCustomProperties_font_size_dirties_subtree(t *testing.T) {
+	root := NewNodeWithConfig(NewConfig())
+	root.StyleSetCustomProperty("--size", Value{Value: 10, Unit: UnitPoint})
+	root.StyleSetFontSizeValue(ValueVar("--size"))
+	child := NewNodeWithConfig(root.Config)
+	child.StyleSetWidthValue(Value{Value: 2, Unit: UnitEm})
+	root.InsertChild(child, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 20, child.LayoutGetWidth())
+
+	root.StyleSetCustomProperty("--size", Value{Value: 12, Unit: UnitPoint})
+	assert.True(t, child.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 24, child.LayoutGetWidth())
+}
+
+func TestCustomProperties_css(t *testing.T) {
+	node := NewNode()
+	err := node.StyleSetCSS("--gap: 4px; --wide: calc(var(--gap) * 2); width: var(--wide, 10px); " +
+		"height: calc(var(--gap) + 50%)")
+	assert.Equal(t, nil, err)
+	value, _ := node.StyleGetCustomProperty("--wide")
+	assert.Equal(t, "calc(var(--gap) * 2)", value.Calc.String())
+	assert.Equal(t, "var(--wide, 10px)", node.StyleGetWidth().Calc.String())
+	assert.Equal(t, "calc(var(--gap) + 50%)", node.StyleGetHeight().Calc.String())
+
+	assert.True(t, node.StyleSetCSS("width: var(gap)") != nil)
+	assert.True(t, node.StyleSetCSS("width: var(--gap") != nil)
+
+	var buf strings.Builder
+	assert.Equal(t, nil, NodeFprint(&buf, node, PrintOptionsStyle, PrintFormatHTML))
+	parsed, err := ParseNodeHTML(buf.String(), nil)
+	assert.Equal(t, nil, err)
+	assert.True(t, styleEq(&node.Style, &parsed.Style))
+}
+
+func TestCustomProperties_stylesheet(t *testing.T) {
+	root := newStyledTree(t, `
+		div { --spacing: 10px }
+		.compact { --spacing: 2px }
+		.item { height: var(--spacing) }
+	`, `<div style="width: 100px"><div class="item"></div><div style="height: 5px"></div></div>`)
+	item, other := root.Children[0], root.Children[1]
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 10, item.LayoutGetHeight())
+
+	// every div sets --spacing, so nothing uses the one of root
+	root.AddClass("compact")
+	assert.False(t, root.IsDirty)
+	assert.False(t, item.IsDirty)
+	assert.False(t, other.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 10, item.LayoutGetHeight())
+
+	item.AddClass("compact")
+	assert.True(t, item.IsDirty)
+	assert.False(t, other.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 2, item.LayoutGetHeight())
+}
+
+func TestCustomProperties_change_reaches_descendants(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetCustomProperty("--size", Value{Value: 20, Unit: UnitPoint})
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexDirection(FlexDirectionRow)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetWidthValue(ValueVar("--size"))
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetWidth())
+
+	root.StyleSetCustomProperty("--size", Value{Value: 40, Unit: UnitPoint})
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetWidth())
+
+	// a node moved to another parent uses the properties of the new one
+	rootChild0.RemoveChild(rootChild0Child0)
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetCustomProperty("--size", Value{Value: 5, Unit: UnitPoint})
+	rootChild1.InsertChild(rootChild0Child0, 0)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetWidth())
+}
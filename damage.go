@@ -0,0 +1,77 @@
+package flex
+
+// DamageTracker computes the regions to redraw after each layout of a tree.
+// It keeps the boxes it last reported itself, so several renderers of the
+// same tree, or trees sharing subtrees, each use their own tracker.
+type DamageTracker struct {
+	root    *Node
+	entries map[*Node]*boxState
+	rects   []Rect
+}
+
+// NewDamageTracker returns a tracker of the layout of root and its
+// descendants, which reports the whole tree on the first call of Damage
+func NewDamageTracker(root *Node) *DamageTracker {
+	return &DamageTracker{
+		root:    root,
+		entries: map[*Node]*boxState{},
+	}
+}
+
+// Damage returns the absolute regions to redraw after CalculateLayout of
+// the root, compared to the previous call: the old and new visible boxes of
+// nodes which moved, were resized, added or removed. Boxes are clipped by
+// ancestors with OverflowHidden or OverflowScroll. Overlapping regions are
+// merged, so the returned rects don't overlap. Only nodes laid out since
+// the previous call and the subtrees of moved nodes are visited.
+func (d *DamageTracker) Damage() []Rect {
+	d.rects = nil
+	updateBoxes(d, d.root, nil, 0, 0, Rect{}, false, false)
+	return mergeRects(d.rects)
+}
+
+func (d *DamageTracker) boxState(node *Node) *boxState {
+	return d.entries[node]
+}
+
+func (d *DamageTracker) setBoxState(node *Node, state *boxState) {
+	if state == nil {
+		delete(d.entries, node)
+	} else {
+		d.entries[node] = state
+	}
+}
+
+func (d *DamageTracker) visibleChanged(node *Node, old Rect, visible Rect) {
+	d.rects = appendRect(d.rects, old)
+	d.rects = appendRect(d.rects, visible)
+}
+
+// appendRect appends r to rects unless it's empty
+func appendRect(rects []Rect, r Rect) []Rect {
+	if r.IsEmpty() {
+		return rects
+	}
+	return append(rects, r)
+}
+
+// mergeRects replaces overlapping rects with their union until none overlap.
+// Each rect is merged with the kept rects, which don't overlap each other,
+// starting over when it grew. As every merge drops a kept rect, there are
+// at most 2*len(rects) passes over them, so it takes O(len(rects)²).
+func mergeRects(rects []Rect) []Rect {
+	// kept reuses rects, it's never longer than the rects already read
+	kept := rects[:0]
+	for _, r := range rects {
+		for i := 0; i < len(kept); i++ {
+			if kept[i].Intersects(r) {
+				r = r.Union(kept[i])
+				kept[i] = kept[len(kept)-1]
+				kept = kept[:len(kept)-1]
+				i = -1
+			}
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
@@ -0,0 +1,147 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDamage(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	var children []*Node
+	for i := 0; i < 4; i++ {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(20)
+		leaf := NewNodeWithConfig(config)
+		leaf.StyleSetHeight(10)
+		child.InsertChild(leaf, 0)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	tracker := NewDamageTracker(root)
+	assert.Equal(t, []Rect{{0, 0, 100, 50}}, tracker.Damage())
+	assert.Equal(t, []Rect(nil), tracker.Damage())
+
+	// growing a child moves the following ones and their children
+	children[2].StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{40, 0, 50, 50}}, tracker.Damage())
+
+	// separate changes give separate rects
+	children[0].Children[0].StyleSetHeight(20)
+	children[3].Children[0].StyleSetHeight(20)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{0, 0, 20, 20}, {70, 0, 20, 20}}, tracker.Damage())
+}
+
+func TestDamage_added_removed(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	var children []*Node
+	for i := 0; i < 4; i++ {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(20)
+		leaf := NewNodeWithConfig(config)
+		leaf.StyleSetHeight(10)
+		child.InsertChild(leaf, 0)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	tracker := NewDamageTracker(root)
+	tracker.Damage()
+
+	root.RemoveChild(children[3])
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{60, 0, 20, 50}}, tracker.Damage())
+
+	added := NewNodeWithConfig(root.Config)
+	added.StyleSetPositionType(PositionTypeAbsolute)
+	added.StyleSetPosition(EdgeLeft, 80)
+	added.StyleSetWidth(5)
+	added.StyleSetHeight(5)
+	root.InsertChild(added, 3)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{80, 0, 5, 5}}, tracker.Damage())
+
+	children[1].StyleSetDisplay(DisplayNone)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{40, 0, 20, 50}, {20, 0, 20, 50}}, tracker.Damage())
+}
+
+func TestDamage_clipping(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	var children []*Node
+	for i := 0; i < 4; i++ {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(20)
+		leaf := NewNodeWithConfig(config)
+		leaf.StyleSetHeight(10)
+		child.InsertChild(leaf, 0)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	tracker := NewDamageTracker(root)
+	root.StyleSetOverflow(OverflowHidden)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	tracker.Damage()
+
+	// the part of the leaf outside of the root isn't drawn
+	children[3].Children[0].StyleSetWidth(60)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{60, 0, 40, 10}}, tracker.Damage())
+
+	// without clipping the leaf is drawn again with the part outside
+	root.StyleSetOverflow(OverflowVisible)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []Rect{{60, 0, 60, 10}}, tracker.Damage())
+}
+
+func TestDamage_separate_trackers(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	child := NewNodeWithConfig(config)
+	child.StyleSetWidth(20)
+	root.InsertChild(child, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// each tracker reports the changes since its own previous call
+	first := NewDamageTracker(root)
+	assert.Equal(t, []Rect{{0, 0, 100, 50}}, first.Damage())
+	child.StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	second := NewDamageTracker(root)
+	assert.Equal(t, []Rect{{0, 0, 100, 50}}, second.Damage())
+	assert.Equal(t, []Rect{{0, 0, 30, 50}}, first.Damage())
+	assert.Equal(t, []Rect(nil), second.Damage())
+
+	// a clone keeping the layout doesn't share the reported boxes
+	clone := root.CloneRecursive(CloneOptionsKeepLayout)
+	assert.Equal(t, []Rect{{0, 0, 100, 50}}, NewDamageTracker(clone).Damage())
+	assert.Equal(t, []Rect(nil), first.Damage())
+}
+
+func TestDamage_merge_rects(t *testing.T) {
+	// the union of the first and the third overlaps the second
+	rects := []Rect{{0, 0, 10, 10}, {20, 20, 10, 10}, {5, 5, 20, 20}, {25, 0, 5, 5}, {100, 0, 5, 5}}
+	assert.Equal(t, []Rect{{0, 0, 30, 30}, {100, 0, 5, 5}}, mergeRects(rects))
+}
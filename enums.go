@@ -22,6 +22,32 @@ const (
 	AlignSpaceBetween
 	// AlignSpaceAround is "space-around"
 	AlignSpaceAround
+	// AlignSpaceEvenly is "space-evenly"
+	AlignSpaceEvenly
+)
+
+// Box is a box of the CSS box model, see LayoutGetBox
+type Box int
+
+const (
+	// BoxBorder is "border-box", the layout width and height
+	BoxBorder Box = iota
+	// BoxPadding is "padding-box", inside the border
+	BoxPadding
+	// BoxContent is "content-box", inside the padding
+	BoxContent
+	// BoxMargin is "margin-box", outside the border box including margins
+	BoxMargin
+)
+
+// BoxSizing is "box-sizing" property
+type BoxSizing int
+
+const (
+	// BoxSizingBorderBox is "border-box"
+	BoxSizingBorderBox BoxSizing = iota
+	// BoxSizingContentBox is "content-box"
+	BoxSizingContentBox
 )
 
 // Dimension represents dimention
@@ -54,6 +80,8 @@ const (
 	DisplayFlex Display = iota
 	// DisplayNone is "none"
 	DisplayNone
+	// DisplayContents is "contents"
+	DisplayContents
 )
 
 // Edge represents an edge
@@ -111,6 +139,22 @@ const (
 	FlexDirectionRowReverse
 )
 
+// Gutter describes which gap is set by "gap" properties
+type Gutter int
+
+const (
+	// GutterColumn is "column-gap"
+	GutterColumn Gutter = iota
+	// GutterRow is "row-gap"
+	GutterRow
+	// GutterAll is "gap"
+	GutterAll
+)
+
+const (
+	gutterCount = 3
+)
+
 // Justify is "justify" property
 type Justify int
 
@@ -125,6 +169,8 @@ const (
 	JustifySpaceBetween
 	// JustifySpaceAround is "space-around"
 	JustifySpaceAround
+	// JustifySpaceEvenly is "space-evenly"
+	JustifySpaceEvenly
 )
 
 // LogLevel represents log level
@@ -177,6 +223,22 @@ const (
 	OverflowScroll
 )
 
+// PointerEvents describes "pointer-events" property, which decides whether
+// HitTest can find a node
+type PointerEvents int
+
+const (
+	// PointerEventsAuto is "auto", the node and its children can be hit
+	PointerEventsAuto PointerEvents = iota
+	// PointerEventsNone is "none", neither the node nor its descendants
+	// can be hit
+	PointerEventsNone
+	// PointerEventsBoxNone is "box-none", only the children can be hit
+	PointerEventsBoxNone
+	// PointerEventsBoxOnly is "box-only", only the node can be hit
+	PointerEventsBoxOnly
+)
+
 // PositionType is "position" property
 type PositionType int
 
@@ -185,6 +247,8 @@ const (
 	PositionTypeRelative PositionType = iota
 	// PositionTypeAbsolute is "absolute"
 	PositionTypeAbsolute
+	// PositionTypeStatic is "static"
+	PositionTypeStatic
 )
 
 type PrintOptions int
@@ -195,6 +259,27 @@ const (
 	PrintOptionsChildren
 )
 
+// CloneOptions controls what Clone and CloneRecursive copy
+type CloneOptions int
+
+const (
+	// CloneOptionsKeepLayout keeps the computed layout and the layout cache,
+	// so an unchanged clone is not laid out again
+	CloneOptionsKeepLayout CloneOptions = 1 << iota
+)
+
+// PrintFormat is the output format of NodeFprint
+type PrintFormat int
+
+const (
+	// PrintFormatHTML is "html"
+	PrintFormatHTML PrintFormat = iota
+	// PrintFormatJSON is "json"
+	PrintFormatJSON
+	// PrintFormatCSS is "css"
+	PrintFormatCSS
+)
+
 // Unit is "unit" property
 type Unit int
 
@@ -207,6 +292,26 @@ const (
 	UnitPercent
 	// UnitAuto is "auto"
 	UnitAuto
+	// UnitMinContent is "min-content"
+	UnitMinContent
+	// UnitMaxContent is "max-content"
+	UnitMaxContent
+	// UnitFitContent is "fit-content", Value is the optional limit in points
+	UnitFitContent
+	// UnitCalc is an expression, see Calc
+	UnitCalc
+	// UnitVw is "vw", percent of the viewport width
+	UnitVw
+	// UnitVh is "vh", percent of the viewport height
+	UnitVh
+	// UnitVmin is "vmin", percent of the smaller viewport dimension
+	UnitVmin
+	// UnitVmax is "vmax", percent of the larger viewport dimension
+	UnitVmax
+	// UnitEm is "em", multiple of the font size of the node
+	UnitEm
+	// UnitRem is "rem", multiple of the font size of the root
+	UnitRem
 )
 
 // Wrap is "wrap" property
@@ -240,6 +345,34 @@ func AlignToString(value Align) string {
 		return "space-between"
 	case AlignSpaceAround:
 		return "space-around"
+	case AlignSpaceEvenly:
+		return "space-evenly"
+	}
+	return "unknown"
+}
+
+// BoxToString returns string version of Box enum
+func BoxToString(value Box) string {
+	switch value {
+	case BoxBorder:
+		return "border-box"
+	case BoxPadding:
+		return "padding-box"
+	case BoxContent:
+		return "content-box"
+	case BoxMargin:
+		return "margin-box"
+	}
+	return "unknown"
+}
+
+// BoxSizingToString returns string version of BoxSizing enum
+func BoxSizingToString(value BoxSizing) string {
+	switch value {
+	case BoxSizingBorderBox:
+		return "border-box"
+	case BoxSizingContentBox:
+		return "content-box"
 	}
 	return "unknown"
 }
@@ -275,6 +408,23 @@ func DisplayToString(value Display) string {
 		return "flex"
 	case DisplayNone:
 		return "none"
+	case DisplayContents:
+		return "contents"
+	}
+	return "unknown"
+}
+
+// PointerEventsToString returns string version of PointerEvents enum
+func PointerEventsToString(value PointerEvents) string {
+	switch value {
+	case PointerEventsAuto:
+		return "auto"
+	case PointerEventsNone:
+		return "none"
+	case PointerEventsBoxNone:
+		return "box-none"
+	case PointerEventsBoxOnly:
+		return "box-only"
 	}
 	return "unknown"
 }
@@ -328,6 +478,19 @@ func FlexDirectionToString(value FlexDirection) string {
 	return "unknown"
 }
 
+// GutterToString returns string version of Gutter enum
+func GutterToString(value Gutter) string {
+	switch value {
+	case GutterColumn:
+		return "column"
+	case GutterRow:
+		return "row"
+	case GutterAll:
+		return "all"
+	}
+	return "unknown"
+}
+
 // JustifyToString returns string version of Justify enum
 func JustifyToString(value Justify) string {
 	switch value {
@@ -341,6 +504,8 @@ func JustifyToString(value Justify) string {
 		return "space-between"
 	case JustifySpaceAround:
 		return "space-around"
+	case JustifySpaceEvenly:
+		return "space-evenly"
 	}
 	return "unknown"
 }
@@ -408,6 +573,8 @@ func PositionTypeToString(value PositionType) string {
 		return "relative"
 	case PositionTypeAbsolute:
 		return "absolute"
+	case PositionTypeStatic:
+		return "static"
 	}
 	return "unknown"
 }
@@ -425,6 +592,28 @@ func PrintOptionsToString(value PrintOptions) string {
 	return "unknown"
 }
 
+// CloneOptionsToString returns string version of CloneOptions enum
+func CloneOptionsToString(value CloneOptions) string {
+	switch value {
+	case CloneOptionsKeepLayout:
+		return "keep-layout"
+	}
+	return "unknown"
+}
+
+// PrintFormatToString returns string version of PrintFormat enum
+func PrintFormatToString(value PrintFormat) string {
+	switch value {
+	case PrintFormatHTML:
+		return "html"
+	case PrintFormatJSON:
+		return "json"
+	case PrintFormatCSS:
+		return "css"
+	}
+	return "unknown"
+}
+
 // UnitToString returns string version of Unit enum
 func UnitToString(value Unit) string {
 	switch value {
@@ -436,6 +625,26 @@ func UnitToString(value Unit) string {
 		return "percent"
 	case UnitAuto:
 		return "auto"
+	case UnitMinContent:
+		return "min-content"
+	case UnitMaxContent:
+		return "max-content"
+	case UnitFitContent:
+		return "fit-content"
+	case UnitCalc:
+		return "calc"
+	case UnitVw:
+		return "vw"
+	case UnitVh:
+		return "vh"
+	case UnitVmin:
+		return "vmin"
+	case UnitVmax:
+		return "vmax"
+	case UnitEm:
+		return "em"
+	case UnitRem:
+		return "rem"
 	}
 	return "unknown"
 }
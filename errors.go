@@ -0,0 +1,272 @@
+package flex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Errors for invalid input. They are wrapped in NodeError or ConfigError,
+// use errors.Is to check for a specific rule.
+var (
+	ErrChildHasParent          = errors.New("child already has a parent, it must be removed first")
+	ErrChildIsAncestor         = errors.New("child is an ancestor of the node")
+	ErrChildIndexOutOfRange    = errors.New("child index out of range")
+	ErrChildParentMismatch     = errors.New("child has a different parent")
+	ErrMeasureWithChildren     = errors.New("nodes with measure functions cannot have children")
+	ErrResetWithChildren       = errors.New("cannot reset a node which still has children attached")
+	ErrResetWithParent         = errors.New("cannot reset a node still attached to a parent")
+	ErrMarkDirtyWithoutMeasure = errors.New("only leaf nodes with custom measure functions should manually mark themselves as dirty")
+	ErrMissingMeasure          = errors.New("expected node to have custom measure function")
+	ErrBaselineNaN             = errors.New("custom baseline function returned NaN")
+	ErrMultiEdgeLayout         = errors.New("cannot get layout properties of multi-edge shorthands")
+	ErrMissingCalc             = errors.New("value with UnitCalc has no expression")
+	ErrMissingConfig           = errors.New("node has no config")
+	ErrNegativeScaleFactor     = errors.New("scale factor should not be less than zero")
+	ErrNegativeWorkers         = errors.New("parallel workers should not be less than zero")
+	ErrInvalidFontSize         = errors.New("font size should be greater than zero")
+	ErrNegativeViewport        = errors.New("viewport size should not be less than zero")
+)
+
+// NodeError is an error caused by a node
+type NodeError struct {
+	Node *Node
+	Err  error
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("flex: node %s: %s", nodeDescription(e.Node), e.Err)
+}
+
+// nodeDescription describes node by its tag, id and classes, like
+// "div#main.wide", and its index in each ancestor, like "[0 2]" for the
+// third child of the first child of the root
+func nodeDescription(node *Node) string {
+	var sb strings.Builder
+	sb.WriteString(node.tag)
+	if node.id != "" {
+		sb.WriteString("#" + node.id)
+	}
+	for _, class := range node.classes {
+		sb.WriteString("." + class)
+	}
+	var path []string
+	for n := node; n.Parent != nil; n = n.Parent {
+		i := len(n.Parent.Children) - 1
+		for i >= 0 && n.Parent.Children[i] != n {
+			i--
+		}
+		path = append([]string{strconv.Itoa(i)}, path...)
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(" ")
+	}
+	sb.WriteString("[" + strings.Join(path, " ") + "]")
+	return sb.String()
+}
+
+// Unwrap returns the rule that was broken
+func (e *NodeError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigError is an error caused by an invalid config
+type ConfigError struct {
+	Config *Config
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("flex: config: %s", e.Err)
+}
+
+// Unwrap returns the rule that was broken
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that config has valid values
+func (config *Config) Validate() error {
+	var err error
+	switch {
+	case config.PointScaleFactor < 0:
+		err = ErrNegativeScaleFactor
+	case config.ParallelWorkers < 0:
+		err = ErrNegativeWorkers
+	case !(config.FontSize > 0):
+		err = ErrInvalidFontSize
+	case config.ViewportWidth < 0 || config.ViewportHeight < 0:
+		err = ErrNegativeViewport
+	}
+	if err != nil {
+		return &ConfigError{Config: config, Err: err}
+	}
+	return nil
+}
+
+// SetPointScaleFactorE is like SetPointScaleFactor but returns an error
+// instead of panicking.
+func (config *Config) SetPointScaleFactorE(pixelsInPoint float32) error {
+	if pixelsInPoint < 0 {
+		return &ConfigError{Config: config, Err: ErrNegativeScaleFactor}
+	}
+	config.SetPointScaleFactor(pixelsInPoint)
+	return nil
+}
+
+// InsertChildE is like InsertChild but returns an error instead of panicking
+func (node *Node) InsertChildE(child *Node, idx int) error {
+	if child.Parent != nil {
+		return &NodeError{Node: child, Err: ErrChildHasParent}
+	}
+	if node.Measure != nil {
+		return &NodeError{Node: node, Err: ErrMeasureWithChildren}
+	}
+	if idx < 0 || idx > len(node.Children) {
+		return &NodeError{Node: node, Err: ErrChildIndexOutOfRange}
+	}
+	for ancestor := node; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor == child {
+			return &NodeError{Node: child, Err: ErrChildIsAncestor}
+		}
+	}
+	node.insertChild(child, idx)
+	return nil
+}
+
+// SetMeasureFuncE is like SetMeasureFunc but returns an error instead of
+// panicking.
+func (node *Node) SetMeasureFuncE(measureFunc MeasureFunc) error {
+	if measureFunc != nil && len(node.Children) > 0 {
+		return &NodeError{Node: node, Err: ErrMeasureWithChildren}
+	}
+	node.SetMeasureFunc(measureFunc)
+	return nil
+}
+
+// CalculateLayoutE is like CalculateLayout but validates the tree first and
+// returns an error instead of panicking. If an error is returned during
+// layout, the layout of the tree is incomplete.
+func CalculateLayoutE(node *Node, parentWidth float32, parentHeight float32, parentDirection Direction) (err error) {
+	if err := ValidateTree(node); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			nodeErr, ok := r.(*NodeError)
+			if !ok {
+				panic(r)
+			}
+			err = nodeErr
+		}
+	}()
+	CalculateLayout(node, parentWidth, parentHeight, parentDirection)
+	return nil
+}
+
+// ValidateTree checks node and its descendants for input that would make
+// CalculateLayout panic or loop.
+func ValidateTree(node *Node) error {
+	return validateTree(node, map[*Node]bool{}, map[*Config]bool{})
+}
+
+// validateTree validates node and its descendants. ancestors has the nodes
+// on the path from the root to node, so a subtree shared by several nodes in
+// copy-on-write mode is not a cycle.
+func validateTree(node *Node, ancestors map[*Node]bool, validConfigs map[*Config]bool) error {
+	if ancestors[node] {
+		return &NodeError{Node: node, Err: ErrChildIsAncestor}
+	}
+	ancestors[node] = true
+	defer delete(ancestors, node)
+
+	if node.Config == nil {
+		return &NodeError{Node: node, Err: ErrMissingConfig}
+	}
+	if !validConfigs[node.Config] {
+		if err := node.Config.Validate(); err != nil {
+			return err
+		}
+		validConfigs[node.Config] = true
+	}
+	if node.Measure != nil && len(node.Children) > 0 {
+		return &NodeError{Node: node, Err: ErrMeasureWithChildren}
+	}
+	if !styleHasCalcs(&node.Style) {
+		return &NodeError{Node: node, Err: ErrMissingCalc}
+	}
+
+	for _, child := range node.Children {
+		if child.Parent != node && !node.Config.CopyOnWrite {
+			return &NodeError{Node: child, Err: ErrChildParentMismatch}
+		}
+		if err := validateTree(child, ancestors, validConfigs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// styleHasCalcs returns false if a value with UnitCalc has no expression
+func styleHasCalcs(style *Style) bool {
+	for _, value := range styleValues(style) {
+		if value.Unit == UnitCalc && value.Calc == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SyntaxError is an error in parsed text, like an unknown CSS property
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the text
+	Offset int
+	// Line and Column are 1-based, Column is counted in bytes
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("flex: %d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// SyntaxErrors is a list of errors found in parsed text
+type SyntaxErrors []*SyntaxError
+
+func (e SyntaxErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors, for errors.As
+func (e SyntaxErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// newSyntaxError returns an error at offset in text
+func newSyntaxError(text string, offset int, format string, args ...interface{}) *SyntaxError {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	line := 1 + strings.Count(text[:offset], "\n")
+	column := offset + 1
+	if i := strings.LastIndexByte(text[:offset], '\n'); i >= 0 {
+		column = offset - i
+	}
+	return &SyntaxError{
+		Offset: offset,
+		Line:   line,
+		Column: column,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
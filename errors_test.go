@@ -0,0 +1,194 @@
+package flex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertChildE_child_with_parent(t *testing.T) {
+	root := NewNode()
+	other := NewNode()
+	child := NewNode()
+	other.InsertChild(child, 0)
+
+	err := root.InsertChildE(child, 0)
+	assert.True(t, errors.Is(err, ErrChildHasParent))
+	var nodeErr *NodeError
+	assert.True(t, errors.As(err, &nodeErr))
+	assert.Equal(t, child, nodeErr.Node)
+	assert.Equal(t, 0, len(root.Children))
+}
+
+func TestInsertChildE_measure_func(t *testing.T) {
+	root := NewNode()
+	root.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{}
+	})
+
+	err := root.InsertChildE(NewNode(), 0)
+	assert.True(t, errors.Is(err, ErrMeasureWithChildren))
+}
+
+func TestInsertChildE_index_out_of_range(t *testing.T) {
+	root := NewNode()
+	err := root.InsertChildE(NewNode(), 1)
+	assert.True(t, errors.Is(err, ErrChildIndexOutOfRange))
+	err = root.InsertChildE(NewNode(), -1)
+	assert.True(t, errors.Is(err, ErrChildIndexOutOfRange))
+}
+
+func TestInsertChildE_cycle(t *testing.T) {
+	root := NewNode()
+	child := NewNode()
+	root.InsertChild(child, 0)
+
+	err := child.InsertChildE(root, 0)
+	assert.True(t, errors.Is(err, ErrChildIsAncestor))
+	err = root.InsertChildE(root, 0)
+	assert.True(t, errors.Is(err, ErrChildIsAncestor))
+}
+
+func TestInsertChild_panics_with_node_error(t *testing.T) {
+	root := NewNode()
+	child := NewNode()
+	root.InsertChild(child, 0)
+
+	defer func() {
+		err, ok := recover().(error)
+		assert.True(t, ok)
+		assert.True(t, errors.Is(err, ErrChildHasParent))
+	}()
+	root.InsertChild(child, 0)
+}
+
+func TestSetMeasureFuncE_with_children(t *testing.T) {
+	root := NewNode()
+	root.InsertChild(NewNode(), 0)
+
+	err := root.SetMeasureFuncE(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{}
+	})
+	assert.True(t, errors.Is(err, ErrMeasureWithChildren))
+	assert.True(t, root.Measure == nil)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := NewConfig()
+	assert.Equal(t, nil, config.Validate())
+
+	config.PointScaleFactor = -1
+	err := config.Validate()
+	assert.True(t, errors.Is(err, ErrNegativeScaleFactor))
+	var configErr *ConfigError
+	assert.True(t, errors.As(err, &configErr))
+	assert.Equal(t, config, configErr.Config)
+
+	config = NewConfig()
+	config.FontSize = 0
+	assert.True(t, errors.Is(config.Validate(), ErrInvalidFontSize))
+
+	config = NewConfig()
+	config.ViewportWidth = -10
+	assert.True(t, errors.Is(config.Validate(), ErrNegativeViewport))
+
+	config = NewConfig()
+	assert.True(t, errors.Is(config.SetPointScaleFactorE(-2), ErrNegativeScaleFactor))
+	assert.Equal(t, nil, config.SetPointScaleFactorE(2))
+	assertFloatEqual(t, 2, config.PointScaleFactor)
+}
+
+func TestCalculateLayoutE(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	child := NewNode()
+	child.StyleSetFlexGrow(1)
+	root.InsertChild(child, 0)
+
+	assert.Equal(t, nil, CalculateLayoutE(root, Undefined, Undefined, DirectionLTR))
+	assertFloatEqual(t, 100, child.LayoutGetHeight())
+}
+
+func TestCalculateLayoutE_invalid_tree(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	child := NewNodeWithConfig(config)
+	root.InsertChild(child, 0)
+	child.Style.Dimensions[DimensionWidth] = Value{Unit: UnitCalc}
+
+	err := CalculateLayoutE(root, 100, 100, DirectionLTR)
+	assert.True(t, errors.Is(err, ErrMissingCalc))
+	var nodeErr *NodeError
+	assert.True(t, errors.As(err, &nodeErr))
+	assert.Equal(t, child, nodeErr.Node)
+
+	child.Style.Dimensions[DimensionWidth] = ValueAuto
+	child.Parent = nil
+	err = CalculateLayoutE(root, 100, 100, DirectionLTR)
+	assert.True(t, errors.Is(err, ErrChildParentMismatch))
+
+	child.Parent = root
+	config.PointScaleFactor = -1
+	err = CalculateLayoutE(root, 100, 100, DirectionLTR)
+	assert.True(t, errors.Is(err, ErrNegativeScaleFactor))
+}
+
+func TestCalculateLayoutE_baseline_nan(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetAlignItems(AlignBaseline)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	child := NewNode()
+	child.StyleSetWidth(10)
+	child.StyleSetHeight(10)
+	child.Baseline = func(node *Node, width float32, height float32) float32 {
+		return Undefined
+	}
+	root.InsertChild(child, 0)
+
+	err := CalculateLayoutE(root, Undefined, Undefined, DirectionLTR)
+	assert.True(t, errors.Is(err, ErrBaselineNaN))
+}
+
+func TestNodeError_describes_node(t *testing.T) {
+	root := NewNode()
+	root.InsertChild(NewNode(), 0)
+	child := NewNode()
+	root.InsertChild(child, 1)
+	grandChild := NewNode()
+	child.InsertChild(grandChild, 0)
+
+	err := &NodeError{Node: grandChild, Err: ErrMissingConfig}
+	assert.Equal(t, "flex: node [1 0]: node has no config", err.Error())
+	grandChild.SetTag("section")
+	grandChild.SetID("main")
+	grandChild.SetClasses("wide")
+	assert.Equal(t, "flex: node section#main.wide [1 0]: node has no config", err.Error())
+	err = &NodeError{Node: root, Err: ErrMissingConfig}
+	assert.Equal(t, "flex: node []: node has no config", err.Error())
+}
+
+func TestValidateTree_cycle(t *testing.T) {
+	root := NewNode()
+	child := NewNode()
+	root.InsertChild(child, 0)
+	child.Children = append(child.Children, root)
+	root.Parent = child
+	assert.True(t, errors.Is(ValidateTree(root), ErrChildIsAncestor))
+}
+
+func TestValidateTree_shared_subtree(t *testing.T) {
+	config := NewConfig()
+	config.CopyOnWrite = true
+	root := NewNodeWithConfig(config)
+	shared := NewNodeWithConfig(config)
+	root.InsertChild(shared, 0)
+	root.Children = append(root.Children, shared)
+	assert.Equal(t, nil, ValidateTree(root))
+
+	root.Children = append(root.Children, root)
+	assert.True(t, errors.Is(ValidateTree(root), ErrChildIsAncestor))
+}
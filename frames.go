@@ -0,0 +1,38 @@
+package flex
+
+// FrameChange is a node whose frame, its border box relative to its parent,
+// changed between two calls of ChangedFrames
+type FrameChange struct {
+	Node *Node
+	Old  Rect
+	New  Rect
+}
+
+// ChangedFrames returns the nodes under root, including root, whose frame
+// changed since the previous call, for repainting only what moved or was
+// resized. Call it after CalculateLayout. Old is empty for nodes which
+// weren't reported before. Only nodes laid out since the previous call are
+// visited, with the subtrees of the others skipped. HasNewLayout is not
+// reset.
+func ChangedFrames(root *Node) []FrameChange {
+	return nodeChangedFrames(root, nil)
+}
+
+func nodeChangedFrames(node *Node, changes []FrameChange) []FrameChange {
+	if node.frameVersion == node.layoutVersion {
+		return changes
+	}
+	node.frameVersion = node.layoutVersion
+	frame := node.LayoutGetBox(BoxBorder)
+	if frame != node.frame {
+		changes = append(changes, FrameChange{Node: node, Old: node.frame, New: frame})
+		node.frame = frame
+	}
+	for _, child := range node.Children {
+		// children shared with another tree keep the frames of that tree
+		if child.Parent == node {
+			changes = nodeChangedFrames(child, changes)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,85 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedFrames(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	var children []*Node
+	for i := 0; i < 3; i++ {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(20)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+	leaf := NewNodeWithConfig(config)
+	leaf.StyleSetHeight(10)
+	children[2].InsertChild(leaf, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// the first call reports all nodes
+	changes := ChangedFrames(root)
+	assert.Equal(t, 5, len(changes))
+	assert.Equal(t, FrameChange{Node: root, New: Rect{0, 0, 100, 50}}, changes[0])
+	assert.True(t, root.HasNewLayout())
+	assert.Equal(t, 0, len(ChangedFrames(root)))
+
+	// growing the first child moves the others but not their children
+	children[0].StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	changes = ChangedFrames(root)
+	assert.Equal(t, []FrameChange{
+		{Node: children[0], Old: Rect{0, 0, 20, 50}, New: Rect{0, 0, 30, 50}},
+		{Node: children[1], Old: Rect{20, 0, 20, 50}, New: Rect{30, 0, 20, 50}},
+		{Node: children[2], Old: Rect{40, 0, 20, 50}, New: Rect{50, 0, 20, 50}},
+	}, changes)
+
+	// a node laid out again at the same frame isn't reported
+	children[1].StyleSetWidth(20)
+	children[2].StyleSetDisplay(DisplayNone)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	changes = ChangedFrames(root)
+	assert.Equal(t, []FrameChange{
+		{Node: children[2], Old: Rect{50, 0, 20, 50}, New: Rect{}},
+		{Node: leaf, Old: Rect{0, 0, 20, 10}, New: Rect{}},
+	}, changes)
+
+	leaf.SetHasNewLayout(true)
+	assert.True(t, leaf.HasNewLayout())
+}
+
+func TestChangedFrames_with_damage_and_spatial_index(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	child0 := NewNodeWithConfig(config)
+	child0.StyleSetWidth(20)
+	root.InsertChild(child0, 0)
+	child1 := NewNodeWithConfig(config)
+	child1.StyleSetWidth(20)
+	root.InsertChild(child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	idx := NewSpatialIndex(root, 10)
+	tracker := NewDamageTracker(root)
+	assert.Equal(t, []Rect{{0, 0, 100, 50}}, tracker.Damage())
+	assert.Equal(t, 3, len(ChangedFrames(root)))
+
+	// each of them sees the changes since it last looked
+	child0.StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 2, len(ChangedFrames(root)))
+	assert.Equal(t, []Rect{{0, 0, 50, 50}}, tracker.Damage())
+	idx.Update()
+	assert.Equal(t, nodeSet([]*Node{root, child1}), nodeSet(idx.At(45, 5)))
+	assert.True(t, child1.HasNewLayout())
+}
@@ -0,0 +1,403 @@
+package flex
+
+import "testing"
+
+func TestGap_column_gap_flexible(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetGap(GutterColumn, 10)
+	root.StyleSetWidth(80)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetFlexShrink(1)
+	rootChild0.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	rootChild1.StyleSetFlexShrink(1)
+	rootChild1.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetFlexGrow(1)
+	rootChild2.StyleSetFlexShrink(1)
+	rootChild2.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetHeight())
+}
+
+func TestGap_wrap_row(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetGap(GutterColumn, 10)
+	root.StyleSetGap(GutterRow, 20)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	rootChild1.StyleSetHeight(20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	rootChild2.StyleSetHeight(20)
+	root.InsertChild(rootChild2, 2)
+
+	rootChild3 := NewNodeWithConfig(config)
+	rootChild3.StyleSetWidth(20)
+	rootChild3.StyleSetHeight(20)
+	root.InsertChild(rootChild3, 3)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild3.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 80, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 20, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 80, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild3.LayoutGetHeight())
+}
+
+func TestGap_wrap_column(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetGap(GutterRow, 10)
+	root.StyleSetGap(GutterColumn, 5)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(30)
+	rootChild0.StyleSetHeight(30)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(30)
+	rootChild1.StyleSetHeight(30)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(30)
+	rootChild2.StyleSetHeight(30)
+	root.InsertChild(rootChild2, 2)
+
+	rootChild3 := NewNodeWithConfig(config)
+	rootChild3.StyleSetWidth(30)
+	rootChild3.StyleSetHeight(30)
+	root.InsertChild(rootChild3, 3)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 65, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 35, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 35, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild3.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 65, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 35, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 35, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild3.LayoutGetHeight())
+}
+
+func TestGap_justify_space_between(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceBetween)
+	root.StyleSetGap(GutterAll, 10)
+	root.StyleSetWidth(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	rootChild2.StyleSetHeight(10)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 10, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 80, rootChild2.LayoutGetLeft())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 80, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+}
+
+func TestGap_row_gap_align_content_flex_end(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetAlignContent(AlignFlexEnd)
+	root.StyleSetGap(GutterRow, 20)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(120)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(60)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(60)
+	rootChild1.StyleSetHeight(20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(60)
+	rootChild2.StyleSetHeight(20)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 120, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetTop())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 60, rootChild1.LayoutGetTop())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetTop())
+}
+
+func TestGap_percent(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetGapPercent(GutterColumn, 10)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(50)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 90, rootChild0.LayoutGetWidth())
+
+	assertFloatEqual(t, 110, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 90, rootChild1.LayoutGetWidth())
+}
+
+func TestGap_percent_of_undefined_size(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetGapPercent(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	rootChild1.StyleSetHeight(20)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 40, root.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetLeft())
+}
+
+func TestGap_content_size(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetGap(GutterColumn, 15)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1.StyleSetWidth(10)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	rootChild2.StyleSetHeight(20)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 55, root.LayoutGetWidth())
+	assertFloatEqual(t, 20, root.LayoutGetHeight())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 35, rootChild2.LayoutGetLeft())
+}
@@ -0,0 +1,52 @@
+package flex
+
+// HitTest returns the path from root to the deepest node containing the
+// point x, y after CalculateLayout. The point is in the coordinate space the
+// position of root is relative to. Later children are on top of earlier ones
+// and absolute children on top of the others. Children outside of a node
+// can be hit unless it has OverflowHidden or OverflowScroll. Nodes with
+// DisplayNone are skipped and Style.PointerEvents decides which nodes can be
+// hit. HitTest returns nil if nothing is hit.
+func HitTest(root *Node, x float32, y float32) []*Node {
+	path := nodeHitTest(root, x, y)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// nodeHitTest returns the path from the deepest hit node to node, for x, y
+// relative to the parent of node
+func nodeHitTest(node *Node, x float32, y float32) []*Node {
+	style := &node.Style
+	if style.Display == DisplayNone || style.PointerEvents == PointerEventsNone {
+		return nil
+	}
+	x -= node.Layout.Position[EdgeLeft]
+	y -= node.Layout.Position[EdgeTop]
+
+	// children of display: contents nodes are laid out by its parent and
+	// it has no box of its own
+	contents := style.Display == DisplayContents
+	inside := !contents && x >= 0 && y >= 0 &&
+		x < node.Layout.Dimensions[DimensionWidth] && y < node.Layout.Dimensions[DimensionHeight]
+	clipped := !contents && style.Overflow != OverflowVisible
+
+	if style.PointerEvents != PointerEventsBoxOnly && (inside || !clipped) {
+		for _, absolute := range []bool{true, false} {
+			for i := len(node.Children) - 1; i >= 0; i-- {
+				child := node.Children[i]
+				if (child.Style.PositionType == PositionTypeAbsolute) != absolute {
+					continue
+				}
+				if path := nodeHitTest(child, x, y); path != nil {
+					return append(path, node)
+				}
+			}
+		}
+	}
+	if inside && style.PointerEvents != PointerEventsBoxNone {
+		return []*Node{node}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func hitTestIDs(root *Node, x, y float32) []string {
+	var ids []string
+	for _, node := range HitTest(root, x, y) {
+		ids = append(ids, node.ID())
+	}
+	return ids
+}
+
+func TestHitTest_deepest_node(t *testing.T) {
+	root, err := ParseNodeHTML(`<div id="root" style="width: 100px; height: 100px; flex-direction: row">
+		<div id="a" style="width: 50px">
+			<div id="a1" style="height: 20px"></div>
+			<div id="a2" style="height: 20px; width: 80px"></div>
+		</div>
+		<div id="b" style="width: 50px; height: 20px; overflow: hidden">
+			<div id="b1" style="height: 20px; width: 80px; margin-left: -30px"></div>
+		</div>
+		<div id="abs" style="position: absolute; left: 40px; top: 0; width: 20px; height: 10px"></div>
+		<div id="hidden" style="display: none"></div>
+	</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, []string{"root", "a", "a1"}, hitTestIDs(root, 5, 5))
+	assert.Equal(t, []string{"root", "a", "a2"}, hitTestIDs(root, 5, 25))
+	assert.Equal(t, []string{"root", "a"}, hitTestIDs(root, 5, 45))
+	assert.Equal(t, []string{"root"}, hitTestIDs(root, 99, 99))
+	assert.Equal(t, []string(nil), hitTestIDs(root, 100, 5))
+	assert.Equal(t, []string(nil), hitTestIDs(root, -1, 5))
+}
+
+func TestHitTest_absolute_on_top(t *testing.T) {
+	root, err := ParseNodeHTML(`<div id="root" style="width: 100px; height: 100px; flex-direction: row">
+		<div id="a" style="width: 50px">
+			<div id="a1" style="height: 20px"></div>
+			<div id="a2" style="height: 20px; width: 80px"></div>
+		</div>
+		<div id="b" style="width: 50px; height: 20px; overflow: hidden">
+			<div id="b1" style="height: 20px; width: 80px; margin-left: -30px"></div>
+		</div>
+		<div id="abs" style="position: absolute; left: 40px; top: 0; width: 20px; height: 10px"></div>
+		<div id="hidden" style="display: none"></div>
+	</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, []string{"root", "abs"}, hitTestIDs(root, 45, 5))
+	assert.Equal(t, []string{"root", "b", "b1"}, hitTestIDs(root, 55, 15))
+}
+
+func TestHitTest_overflow(t *testing.T) {
+	root, err := ParseNodeHTML(`<div id="root" style="width: 100px; height: 100px; flex-direction: row">
+		<div id="a" style="width: 50px">
+			<div id="a1" style="height: 20px"></div>
+			<div id="a2" style="height: 20px; width: 80px"></div>
+		</div>
+		<div id="b" style="width: 50px; height: 20px; overflow: hidden">
+			<div id="b1" style="height: 20px; width: 80px; margin-left: -30px"></div>
+		</div>
+		<div id="abs" style="position: absolute; left: 40px; top: 0; width: 20px; height: 10px"></div>
+		<div id="hidden" style="display: none"></div>
+	</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// a2 overflows a, which is visible
+	assert.Equal(t, []string{"root", "a", "a2"}, hitTestIDs(root, 70, 25))
+	// b1 starts left of b, which clips it
+	assert.Equal(t, []string{"root", "a", "a1"}, hitTestIDs(root, 25, 15))
+	assert.Equal(t, []string{"root", "b", "b1"}, hitTestIDs(root, 50, 15))
+
+	root.Style.Overflow = OverflowHidden
+	root.Children[0].Children[1].StyleSetWidth(150)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []string(nil), hitTestIDs(root, 120, 25))
+}
+
+func TestHitTest_pointer_events(t *testing.T) {
+	root, err := ParseNodeHTML(`<div id="root" style="width: 100px; height: 100px; flex-direction: row">
+		<div id="a" style="width: 50px">
+			<div id="a1" style="height: 20px"></div>
+			<div id="a2" style="height: 20px; width: 80px"></div>
+		</div>
+		<div id="b" style="width: 50px; height: 20px; overflow: hidden">
+			<div id="b1" style="height: 20px; width: 80px; margin-left: -30px"></div>
+		</div>
+		<div id="abs" style="position: absolute; left: 40px; top: 0; width: 20px; height: 10px"></div>
+		<div id="hidden" style="display: none"></div>
+	</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	a := root.Children[0]
+	abs := root.Children[2]
+
+	abs.StyleSetPointerEvents(PointerEventsNone)
+	assert.False(t, root.IsDirty)
+	assert.Equal(t, []string{"root", "a", "a1"}, hitTestIDs(root, 45, 5))
+
+	a.StyleSetPointerEvents(PointerEventsBoxOnly)
+	assert.Equal(t, []string{"root", "a"}, hitTestIDs(root, 5, 5))
+
+	a.StyleSetPointerEvents(PointerEventsBoxNone)
+	assert.Equal(t, []string{"root", "a", "a1"}, hitTestIDs(root, 5, 5))
+	assert.Equal(t, []string{"root"}, hitTestIDs(root, 5, 45))
+
+	root.StyleSetPointerEvents(PointerEventsNone)
+	assert.Equal(t, []string(nil), hitTestIDs(root, 5, 5))
+
+	assert.Equal(t, nil, a.StyleSetCSS("pointer-events: box-only"))
+	assert.Equal(t, PointerEventsBoxOnly, a.StyleGetPointerEvents())
+}
+
+func TestHitTest_display(t *testing.T) {
+	root, err := ParseNodeHTML(`<div id="root" style="width: 100px; height: 100px; flex-direction: row">
+		<div id="a" style="width: 50px">
+			<div id="a1" style="height: 20px"></div>
+			<div id="a2" style="height: 20px; width: 80px"></div>
+		</div>
+		<div id="b" style="width: 50px; height: 20px; overflow: hidden">
+			<div id="b1" style="height: 20px; width: 80px; margin-left: -30px"></div>
+		</div>
+		<div id="abs" style="position: absolute; left: 40px; top: 0; width: 20px; height: 10px"></div>
+		<div id="hidden" style="display: none"></div>
+	</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	hidden := root.Children[3]
+	hidden.StyleSetDisplay(DisplayFlex)
+	hidden.StyleSetPositionType(PositionTypeAbsolute)
+	hidden.StyleSetWidth(100)
+	hidden.StyleSetHeight(100)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []string{"root", "hidden"}, hitTestIDs(root, 5, 5))
+
+	hidden.StyleSetDisplay(DisplayNone)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []string{"root", "a", "a1"}, hitTestIDs(root, 5, 5))
+
+	root.Children[1].StyleSetDisplay(DisplayContents)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []string{"root", "b", "b1"}, hitTestIDs(root, 60, 5))
+}
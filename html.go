@@ -0,0 +1,220 @@
+package flex
+
+import (
+	"html"
+	"strings"
+)
+
+// htmlParser parses the HTML-like format written by NodePrint
+type htmlParser struct {
+	src    string
+	pos    int
+	config *Config
+}
+
+func (p *htmlParser) errorf(offset int, format string, args ...interface{}) error {
+	return newSyntaxError(p.src, offset, format, args...)
+}
+
+func (p *htmlParser) skipSpace() {
+	for p.pos < len(p.src) && isCSSSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// skipText skips text and comments between tags
+func (p *htmlParser) skipText() error {
+	for p.pos < len(p.src) {
+		if strings.HasPrefix(p.src[p.pos:], "<!--") {
+			end := strings.Index(p.src[p.pos+4:], "-->")
+			if end < 0 {
+				return p.errorf(p.pos, "unterminated comment")
+			}
+			p.pos += 4 + end + 3
+			continue
+		}
+		if p.src[p.pos] == '<' {
+			return nil
+		}
+		p.pos++
+	}
+	return nil
+}
+
+func (p *htmlParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isCSSIdentChar(p.src[p.pos]) || p.src[p.pos] == ':') {
+		p.pos++
+	}
+	return strings.ToLower(p.src[start:p.pos])
+}
+
+// element parses an element starting at '<' and its children
+func (p *htmlParser) element() (*Node, error) {
+	start := p.pos
+	p.pos++
+	tag := p.name()
+	if tag == "" {
+		return nil, p.errorf(start, "expected element")
+	}
+
+	node := NewNodeWithConfig(p.config)
+	node.tag = tag
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf(start, "unterminated <%s>", tag)
+		}
+		if p.src[p.pos] == '>' {
+			p.pos++
+			break
+		}
+		if strings.HasPrefix(p.src[p.pos:], "/>") {
+			p.pos += 2
+			return node, nil
+		}
+		if err := p.attribute(node); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if err := p.skipText(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf(start, "missing </%s>", tag)
+		}
+		if strings.HasPrefix(p.src[p.pos:], "</") {
+			closeStart := p.pos
+			p.pos += 2
+			if closeTag := p.name(); closeTag != tag {
+				return nil, p.errorf(closeStart, "expected </%s>, got </%s>", tag, closeTag)
+			}
+			p.skipSpace()
+			if p.pos >= len(p.src) || p.src[p.pos] != '>' {
+				return nil, p.errorf(p.pos, "expected '>'")
+			}
+			p.pos++
+			return node, nil
+		}
+		child, err := p.element()
+		if err != nil {
+			return nil, err
+		}
+		node.InsertChild(child, len(node.Children))
+	}
+}
+
+// attribute parses name="value" and applies the style attribute to node
+func (p *htmlParser) attribute(node *Node) error {
+	nameStart := p.pos
+	name := p.name()
+	if name == "" {
+		return p.errorf(p.pos, "unexpected %q in tag", p.src[p.pos])
+	}
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+		// attribute without value, like <div hidden>
+		return nil
+	}
+	p.pos++
+	p.skipSpace()
+
+	var valueStart, valueEnd int
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		quote := p.src[p.pos]
+		valueStart = p.pos + 1
+		end := strings.IndexByte(p.src[valueStart:], quote)
+		if end < 0 {
+			return p.errorf(p.pos, "unterminated attribute value")
+		}
+		valueEnd = valueStart + end
+		p.pos = valueEnd + 1
+	} else {
+		valueStart = p.pos
+		for p.pos < len(p.src) && !isCSSSpace(p.src[p.pos]) && p.src[p.pos] != '>' {
+			p.pos++
+		}
+		valueEnd = p.pos
+	}
+
+	value := p.src[valueStart:valueEnd]
+	switch name {
+	case "id":
+		node.id = html.UnescapeString(value)
+		return nil
+	case "class":
+		node.classes = strings.Fields(html.UnescapeString(value))
+		return nil
+	case "style":
+	default:
+		// layout and has-custom-measure are written by NodePrint but can't
+		// be applied
+		return nil
+	}
+
+	if strings.IndexByte(value, '&') >= 0 {
+		// offsets of errors are only precise without character references
+		value = html.UnescapeString(value)
+		if errs := parseDeclarations(node, nodePrintProperties, value, 0, len(value)); len(errs) > 0 {
+			return p.errorf(nameStart, "%s", errs[0])
+		}
+		return nil
+	}
+	if errs := parseDeclarations(node, nodePrintProperties, p.src, valueStart, valueEnd); len(errs) > 0 {
+		return toSyntaxError(p.src, errs[0])
+	}
+	return nil
+}
+
+// nodePrintFlex parses "flex", which NodePrint writes as a single number
+// for StyleSetFlex. Other values are the CSS shorthand.
+func nodePrintFlex(p *cssParser) (func(node *Node), error) {
+	start := p.pos
+	if f, ok := p.unitlessNumber(); ok && !p.more() {
+		return func(node *Node) { node.StyleSetFlex(f) }, nil
+	}
+	p.pos = start
+	return flexShorthand(p)
+}
+
+// nodePrintProperties are the names which NodePrint writes but CSS doesn't
+// have
+var nodePrintProperties = map[string]cssProperty{
+	"flexWrap":    flexWrapProperty,
+	"flex":        nodePrintFlex,
+	"margin-all":  edgeValueProperty(EdgeAll, (*Node).StyleSetMarginValue),
+	"padding-all": edgeValueProperty(EdgeAll, (*Node).StyleSetPaddingValue),
+	"border-all":  borderProperty(EdgeAll),
+}
+
+// ParseNodeHTML creates a tree of nodes from the HTML-like format written by
+// NodePrint or from HTML with inline style attributes. Any element is a node.
+// The style attribute sets the base style, and the element name, id and
+// class attributes are used by Config.Stylesheet. If config is nil the
+// default config is used. Errors are *SyntaxError.
+func ParseNodeHTML(text string, config *Config) (*Node, error) {
+	if config == nil {
+		config = &configDefaults
+	}
+	p := &htmlParser{src: text, config: config}
+	if err := p.skipText(); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(text) {
+		return nil, p.errorf(p.pos, "expected element")
+	}
+	node, err := p.element()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipText(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(text) {
+		return nil, p.errorf(p.pos, "unexpected content after root element")
+	}
+	node.Restyle()
+	return node, nil
+}
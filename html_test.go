@@ -0,0 +1,136 @@
+package flex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNodeHTML_fixture(t *testing.T) {
+	root, err := ParseNodeHTML(`
+<div style="align-items: center; width: 100px; height: 100px;">
+  <div style="width: 10px; height: 10px;"></div>
+</div>`, NewConfig())
+	assert.Equal(t, nil, err)
+	rootChild0 := root.GetChild(0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 45, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+}
+
+func TestParseNodeHTML_node_print_round_trip(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRowReverse)
+	root.StyleSetJustifyContent(JustifySpaceBetween)
+	root.StyleSetAlignContent(AlignStretch)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetOverflow(OverflowHidden)
+	root.StyleSetBoxSizing(BoxSizingContentBox)
+	root.StyleSetFontSizeValue(Value{Value: 2, Unit: UnitRem})
+	root.StyleSetPadding(EdgeAll, 5)
+	root.StyleSetPaddingPercent(EdgeLeft, 2)
+	root.StyleSetBorder(EdgeTop, 3)
+	root.StyleSetGap(GutterColumn, 4)
+	root.StyleSetWidth(300)
+	root.StyleSetHeightPercent(50)
+	root.StyleSetMaxWidthValue(Value{Value: 80, Unit: UnitVw})
+
+	child := NewNodeWithConfig(config)
+	child.StyleSetFlexGrow(1)
+	child.StyleSetFlexShrink(0.5)
+	child.StyleSetFlexBasisFitContent(40)
+	child.StyleSetFlex(2)
+	child.StyleSetAlignSelf(AlignFlexEnd)
+	child.StyleSetMarginAuto(EdgeLeft)
+	child.StyleSetMarginAuto(EdgeRight)
+	child.StyleSetMinWidthValue(Value{Value: 2, Unit: UnitEm})
+	child.StyleSetMaxWidthCalc(CalcSub(CalcPercent(50), CalcMul(CalcPoint(10), 2)))
+	child.StyleSetMaxHeightCalc(CalcClamp(CalcPoint(10), CalcValue(Value{Value: 5, Unit: UnitVh}), CalcPercent(90)))
+	root.InsertChild(child, 0)
+
+	abs := NewNodeWithConfig(config)
+	abs.StyleSetPositionType(PositionTypeAbsolute)
+	abs.StyleSetPosition(EdgeRight, 7)
+	abs.StyleSetPositionPercent(EdgeTop, 10)
+	abs.StyleSetDisplay(DisplayNone)
+	abs.StyleSetWidthMinContent()
+	root.InsertChild(abs, 1)
+
+	var sb strings.Builder
+	err := NodeFprint(&sb, root, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren, PrintFormatHTML)
+	assert.Equal(t, nil, err)
+
+	parsed, err := ParseNodeHTML(sb.String(), config)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(parsed.Children))
+	assert.True(t, styleEq(&root.Style, &parsed.Style))
+	for i := range root.Children {
+		assert.True(t, styleEq(&root.Children[i].Style, &parsed.Children[i].Style))
+		assert.Equal(t, parsed, parsed.Children[i].Parent)
+	}
+}
+
+func TestParseNodeHTML_html_subset(t *testing.T) {
+	root, err := ParseNodeHTML(`<!-- header -->
+<section style='flex-direction: row; width: 90px; height: 20px'>
+  <span style="flex: 1">one</span>
+  <img style="width: 26px" />
+  <p hidden class=x style="flex-grow: 2; flex-wrap: nowrap; margin: calc(1px + 1px)"></p>
+</section>
+`, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(root.Children))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 20, root.GetChild(0).LayoutGetWidth())
+	assertFloatEqual(t, 26, root.GetChild(1).LayoutGetWidth())
+	assertFloatEqual(t, 48, root.GetChild(2).LayoutGetLeft())
+	assertFloatEqual(t, 40, root.GetChild(2).LayoutGetWidth())
+}
+
+func TestParseNodeHTML_character_references(t *testing.T) {
+	root, err := ParseNodeHTML(`<div style="width: 10px;&#32;height: 20px"></div>`, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Value{Value: 20, Unit: UnitPoint}, root.StyleGetHeight())
+}
+
+func TestParseNodeHTML_errors(t *testing.T) {
+	tests := []struct {
+		text   string
+		line   int
+		column int
+		msg    string
+	}{
+		{"", 1, 1, "expected element"},
+		{"<div>", 1, 1, "missing </div>"},
+		{"<div></span>", 1, 6, "expected </div>, got </span>"},
+		{"<div></div><div></div>", 1, 12, "unexpected content after root element"},
+		{"<div>\n  <div style=\"widht: 10px\"></div>\n</div>", 2, 15, `unknown property "widht"`},
+		{"<div style=\"width: 10pt\">", 1, 22, `unknown unit "pt"`},
+		{"<div style=\"width: 10px 20px\">", 1, 25, `unexpected value, got "20px"`},
+		{"<div style=\"align-items: middle\">", 1, 26, `unknown keyword, got "middle"`},
+		{"<div style=\"width: fit-content(10%)\">", 1, 20, "fit-content() limit must be in px"},
+		{"<div style=\"width: calc(10px + )\">", 1, 32, `expected value, got ")"`},
+		{"<div style=\"border: 5%\">", 1, 21, "border must be in px"},
+	}
+	for _, test := range tests {
+		_, err := ParseNodeHTML(test.text, nil)
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr), test.text)
+		if syntaxErr == nil {
+			continue
+		}
+		assert.Equal(t, test.line, syntaxErr.Line, test.text)
+		assert.Equal(t, test.column, syntaxErr.Column, test.text)
+		assert.Equal(t, test.msg, syntaxErr.Msg, test.text)
+	}
+}
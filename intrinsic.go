@@ -0,0 +1,148 @@
+package flex
+
+// isIntrinsicUnit returns true for units sized from the content of the node.
+func isIntrinsicUnit(unit Unit) bool {
+	return unit == UnitMinContent || unit == UnitMaxContent || unit == UnitFitContent
+}
+
+// nodeResolveIntrinsicDimensions replaces intrinsic width and height of node
+// with point values computed from its content. availableWidth and
+// availableHeight are the inner size of the parent.
+func nodeResolveIntrinsicDimensions(node *Node, availableWidth float32, availableHeight float32, direction Direction, config *Config, ctx *layoutContext) {
+	for _, axis := range []FlexDirection{FlexDirectionRow, FlexDirectionColumn} {
+		value := node.resolvedDimensions[dim[axis]]
+		if !isIntrinsicUnit(value.Unit) {
+			continue
+		}
+		size := nodeIntrinsicSize(node, value, axis, availableWidth, availableHeight, direction, config, ctx)
+		if node.Style.BoxSizing == BoxSizingContentBox {
+			size -= nodePaddingAndBorderForAxis(node, axis, availableWidth)
+		}
+		node.intrinsicDimensions[dim[axis]] = Value{Value: size, Unit: UnitPoint}
+		node.resolvedDimensions[dim[axis]] = &node.intrinsicDimensions[dim[axis]]
+	}
+}
+
+// nodeIntrinsicSize returns the border-box size of node along axis for an
+// intrinsic value. Heights are always sized to their max-content, as there is
+// no narrower layout to pick from once the width is known.
+func nodeIntrinsicSize(node *Node, value *Value, axis FlexDirection, availableWidth float32, availableHeight float32, direction Direction, config *Config, ctx *layoutContext) float32 {
+	if flexDirectionIsColumn(axis) {
+		return nodeMaxContentSize(node, axis, availableWidth, availableHeight, direction, config, ctx)
+	}
+
+	switch value.Unit {
+	case UnitMinContent:
+		return nodeMinContentWidth(node, direction, config, ctx)
+	case UnitFitContent:
+		limit := value.Value
+		if FloatIsUndefined(limit) {
+			limit = availableWidth - nodeMarginForAxis(node, FlexDirectionRow, availableWidth)
+		}
+		maxContent := nodeMaxContentSize(node, axis, availableWidth, availableHeight, direction, config, ctx)
+		if FloatIsUndefined(limit) {
+			return maxContent
+		}
+		return fminf(maxContent, fmaxf(nodeMinContentWidth(node, direction, config, ctx), limit))
+	}
+	return nodeMaxContentSize(node, axis, availableWidth, availableHeight, direction, config, ctx)
+}
+
+// nodeMaxContentSize measures node without constraining its width. When
+// measuring height, the width of node is used if it is known.
+func nodeMaxContentSize(node *Node, axis FlexDirection, availableWidth float32, availableHeight float32, direction Direction, config *Config, ctx *layoutContext) float32 {
+	width := Undefined
+	widthMeasureMode := MeasureModeUndefined
+	if flexDirectionIsColumn(axis) {
+		if nodeIsStyleDimDefined(node, FlexDirectionRow, availableWidth) {
+			width = nodeResolveBoxSize(node, node.resolvedDimensions[DimensionWidth], FlexDirectionRow, availableWidth, availableWidth) +
+				nodeMarginForAxis(node, FlexDirectionRow, availableWidth)
+			widthMeasureMode = MeasureModeExactly
+		} else if !FloatIsUndefined(availableWidth) {
+			width = availableWidth
+			widthMeasureMode = MeasureModeAtMost
+		}
+	}
+
+	layoutNodeInternal(node,
+		width,
+		Undefined,
+		direction,
+		widthMeasureMode,
+		MeasureModeUndefined,
+		availableWidth,
+		availableHeight,
+		false,
+		"intrinsic",
+		config,
+		ctx)
+	return node.Layout.measuredDimensions[dim[axis]]
+}
+
+// nodeMinContentWidth returns the narrowest border-box width of node that
+// doesn't overflow its content. Measure funcs are asked for their width when
+// given no space, containers add up or take the widest of their children.
+func nodeMinContentWidth(node *Node, direction Direction, config *Config, ctx *layoutContext) float32 {
+	var content float32
+	if node.Measure != nil {
+		content = node.Measure(node, 0, MeasureModeAtMost, Undefined, MeasureModeUndefined).Width
+	} else {
+		direction = nodeResolveDirection(node, direction)
+		mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
+		isSingleLineRow := flexDirectionIsRow(mainAxis) && node.Style.FlexWrap == WrapNoWrap
+
+		// node may not have been laid out in this pass yet
+		nodeUpdateChildUnitBases(node, ctx.generationCount)
+		itemCount := 0
+		for _, child := range nodeLayoutChildren(node) {
+			if child.Style.Display == DisplayNone || child.Style.PositionType == PositionTypeAbsolute {
+				continue
+			}
+			childWidth := nodeMinContentContribution(child, direction, config, ctx)
+			if isSingleLineRow {
+				content += childWidth
+			} else {
+				content = fmaxf(content, childWidth)
+			}
+			itemCount++
+		}
+		if isSingleLineRow && itemCount > 1 {
+			content += float32(itemCount-1) * nodeGapForAxis(node, mainAxis, Undefined)
+		}
+	}
+
+	return nodeBoundAxis(node,
+		FlexDirectionRow,
+		content+nodePaddingAndBorderForAxis(node, FlexDirectionRow, Undefined),
+		Undefined,
+		Undefined)
+}
+
+// nodeMinContentContribution returns the width child takes up, including
+// margins, when its parent is sized to min-content.
+func nodeMinContentContribution(child *Node, direction Direction, config *Config, ctx *layoutContext) float32 {
+	value := &child.Style.Dimensions[DimensionWidth]
+	var width float32
+	switch {
+	case value.Unit != UnitPercent && !isIntrinsicUnit(value.Unit) &&
+		!(value.Unit == UnitCalc && value.Calc.hasPercent()) &&
+		!FloatIsUndefined(nodeResolveValue(child, value, Undefined)):
+		width = nodeBoundAxis(child,
+			FlexDirectionRow,
+			nodeResolveBoxSize(child, value, FlexDirectionRow, Undefined, Undefined),
+			Undefined,
+			Undefined)
+	case value.Unit == UnitMaxContent:
+		width = nodeMaxContentSize(child, FlexDirectionRow, Undefined, Undefined, direction, config, ctx)
+	case value.Unit == UnitFitContent && !FloatIsUndefined(value.Value):
+		width = nodeIntrinsicSize(child, value, FlexDirectionRow, Undefined, Undefined, direction, config, ctx)
+	default:
+		width = nodeMinContentWidth(child, direction, config, ctx)
+	}
+
+	margin := nodeMarginForAxis(child, FlexDirectionRow, Undefined)
+	if FloatIsUndefined(margin) {
+		margin = 0
+	}
+	return width + margin
+}
@@ -0,0 +1,170 @@
+package flex
+
+import "testing"
+
+// measureWords measures a text of node.Context words, each 10 points wide and
+// high, wrapping at word boundaries.
+func measureWords(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+	words := node.Context.(int)
+	textWidth := float32(words * 10)
+	if widthMode != MeasureModeUndefined && width < textWidth {
+		textWidth = fmaxf(10, float32(int(width/10))*10)
+	}
+	wordsPerLine := int(textWidth / 10)
+	lines := (words + wordsPerLine - 1) / wordsPerLine
+	return Size{Width: textWidth, Height: float32(lines * 10)}
+}
+
+func newWordsNode(config *Config, words int) *Node {
+	node := NewNodeWithConfig(config)
+	node.Context = words
+	node.SetMeasureFunc(measureWords)
+	return node
+}
+
+func TestIntrinsic_sizing_max_content(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(500)
+
+	rootChild0 := newWordsNode(config, 5)
+	rootChild0.StyleSetWidthMaxContent()
+	rootChild0.StyleSetPadding(EdgeAll, 5)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 60, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 440, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 60, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+}
+
+func TestIntrinsic_sizing_min_content(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(500)
+
+	rootChild0 := newWordsNode(config, 5)
+	rootChild0.StyleSetWidthMinContent()
+	rootChild0.StyleSetPadding(EdgeAll, 5)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 60, rootChild0.LayoutGetHeight())
+}
+
+func TestIntrinsic_sizing_fit_content(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(200)
+
+	rootChild0 := newWordsNode(config, 5)
+	rootChild0.StyleSetWidthFitContent(30)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := newWordsNode(config, 50)
+	rootChild1.StyleSetWidthFitContent(Undefined)
+	rootChild1.StyleSetMargin(EdgeLeft, 20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := newWordsNode(config, 5)
+	rootChild2.StyleSetWidthFitContent(Undefined)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 30, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 20, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 180, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+}
+
+func TestIntrinsic_sizing_min_content_container(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(500)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexDirection(FlexDirectionRow)
+	rootChild0.StyleSetGap(GutterColumn, 5)
+	rootChild0.StyleSetPadding(EdgeHorizontal, 10)
+	rootChild0.StyleSetWidthMinContent()
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := newWordsNode(config, 3)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child1 := newWordsNode(config, 2)
+	rootChild0Child1.StyleSetMargin(EdgeLeft, 5)
+	rootChild0.InsertChild(rootChild0Child1, 1)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidthMinContent()
+	root.InsertChild(rootChild1, 1)
+
+	rootChild1Child0 := newWordsNode(config, 3)
+	rootChild1.InsertChild(rootChild1Child0, 0)
+
+	rootChild1Child1 := NewNodeWithConfig(config)
+	rootChild1Child1.StyleSetWidth(15)
+	rootChild1Child1.StyleSetHeight(10)
+	rootChild1.InsertChild(rootChild1Child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+
+	assertFloatEqual(t, 15, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetHeight())
+	assertFloatEqual(t, 15, rootChild1Child0.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1Child0.LayoutGetHeight())
+}
+
+func TestIntrinsic_sizing_flex_basis_max_content(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(500)
+	root.StyleSetHeight(100)
+
+	rootChild0 := newWordsNode(config, 4)
+	rootChild0.StyleSetFlexBasisMaxContent()
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 40, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 460, rootChild1.LayoutGetWidth())
+}
+
+func TestIntrinsic_sizing_height_uses_width(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(500)
+	root.StyleSetHeight(500)
+
+	rootChild0 := newWordsNode(config, 4)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeightMinContent()
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+}
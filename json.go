@@ -0,0 +1,521 @@
+package flex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// marshalEnum returns the name of v as text
+func marshalEnum[T ~int](v T, toString func(T) string) ([]byte, error) {
+	s := toString(v)
+	if s == "unknown" {
+		return nil, fmt.Errorf("flex: invalid %T %d", v, int(v))
+	}
+	return []byte(s), nil
+}
+
+// unmarshalEnum sets v from its name
+func unmarshalEnum[T ~int](v *T, text []byte, toString func(T) string) error {
+	for e := T(0); toString(e) != "unknown"; e++ {
+		if toString(e) == string(text) {
+			*v = e
+			return nil
+		}
+	}
+	return fmt.Errorf("flex: invalid %T %q", *v, text)
+}
+
+// MarshalText returns the name of d
+func (d Direction) MarshalText() ([]byte, error) { return marshalEnum(d, DirectionToString) }
+
+// UnmarshalText sets d from its name
+func (d *Direction) UnmarshalText(text []byte) error {
+	return unmarshalEnum(d, text, DirectionToString)
+}
+
+// MarshalText returns the name of d
+func (d FlexDirection) MarshalText() ([]byte, error) { return marshalEnum(d, FlexDirectionToString) }
+
+// UnmarshalText sets d from its name
+func (d *FlexDirection) UnmarshalText(text []byte) error {
+	return unmarshalEnum(d, text, FlexDirectionToString)
+}
+
+// MarshalText returns the name of j
+func (j Justify) MarshalText() ([]byte, error) { return marshalEnum(j, JustifyToString) }
+
+// UnmarshalText sets j from its name
+func (j *Justify) UnmarshalText(text []byte) error {
+	return unmarshalEnum(j, text, JustifyToString)
+}
+
+// MarshalText returns the name of a
+func (a Align) MarshalText() ([]byte, error) { return marshalEnum(a, AlignToString) }
+
+// UnmarshalText sets a from its name
+func (a *Align) UnmarshalText(text []byte) error {
+	return unmarshalEnum(a, text, AlignToString)
+}
+
+// MarshalText returns the name of p
+func (p PositionType) MarshalText() ([]byte, error) { return marshalEnum(p, PositionTypeToString) }
+
+// UnmarshalText sets p from its name
+func (p *PositionType) UnmarshalText(text []byte) error {
+	return unmarshalEnum(p, text, PositionTypeToString)
+}
+
+// MarshalText returns the name of w
+func (w Wrap) MarshalText() ([]byte, error) { return marshalEnum(w, WrapToString) }
+
+// UnmarshalText sets w from its name
+func (w *Wrap) UnmarshalText(text []byte) error {
+	return unmarshalEnum(w, text, WrapToString)
+}
+
+// MarshalText returns the name of o
+func (o Overflow) MarshalText() ([]byte, error) { return marshalEnum(o, OverflowToString) }
+
+// UnmarshalText sets o from its name
+func (o *Overflow) UnmarshalText(text []byte) error {
+	return unmarshalEnum(o, text, OverflowToString)
+}
+
+// MarshalText returns the name of p
+func (p PointerEvents) MarshalText() ([]byte, error) { return marshalEnum(p, PointerEventsToString) }
+
+// UnmarshalText sets p from its name
+func (p *PointerEvents) UnmarshalText(text []byte) error {
+	return unmarshalEnum(p, text, PointerEventsToString)
+}
+
+// MarshalText returns the name of d
+func (d Display) MarshalText() ([]byte, error) { return marshalEnum(d, DisplayToString) }
+
+// UnmarshalText sets d from its name
+func (d *Display) UnmarshalText(text []byte) error {
+	return unmarshalEnum(d, text, DisplayToString)
+}
+
+// MarshalText returns the name of b
+func (b BoxSizing) MarshalText() ([]byte, error) { return marshalEnum(b, BoxSizingToString) }
+
+// UnmarshalText sets b from its name
+func (b *BoxSizing) UnmarshalText(text []byte) error {
+	return unmarshalEnum(b, text, BoxSizingToString)
+}
+
+// MarshalText returns the name of n
+func (n NodeType) MarshalText() ([]byte, error) { return marshalEnum(n, NodeTypeToString) }
+
+// UnmarshalText sets n from its name
+func (n *NodeType) UnmarshalText(text []byte) error {
+	return unmarshalEnum(n, text, NodeTypeToString)
+}
+
+// MarshalText returns value in CSS syntax, like "10px", "50%" or "auto".
+// An undefined value is "undefined".
+func (value Value) MarshalText() ([]byte, error) {
+	if value.Unit == UnitCalc && value.Calc == nil {
+		return nil, ErrMissingCalc
+	}
+	if value.Unit == UnitUndefined {
+		return []byte("undefined"), nil
+	}
+	return []byte(valueToString(&value)), nil
+}
+
+// UnmarshalText sets value from CSS syntax or "undefined"
+func (value *Value) UnmarshalText(text []byte) error {
+	s := string(text)
+	if s == "undefined" {
+		*value = ValueUndefined
+		return nil
+	}
+	p := &cssParser{src: s, end: len(s)}
+	v, err := p.value()
+	if err == nil {
+		err = p.done()
+	}
+	if err != nil {
+		return toSyntaxError(s, err)
+	}
+	*value = v
+	return nil
+}
+
+// jsonFloat is a float32 which is "undefined" in JSON if it's NaN
+type jsonFloat float32
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if FloatIsUndefined(float32(f)) {
+		return []byte(`"undefined"`), nil
+	}
+	return []byte(strconv.FormatFloat(float64(f), 'g', -1, 32)), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	if string(data) == `"undefined"` {
+		*f = jsonFloat(Undefined)
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(data), 32)
+	if err != nil {
+		return fmt.Errorf("flex: invalid number %s", data)
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+type jsonStyle struct {
+	Direction      Direction        `json:"direction"`
+	FlexDirection  FlexDirection    `json:"flexDirection"`
+	JustifyContent Justify          `json:"justifyContent"`
+	AlignContent   Align            `json:"alignContent"`
+	AlignItems     Align            `json:"alignItems"`
+	AlignSelf      Align            `json:"alignSelf"`
+	PositionType   PositionType     `json:"positionType"`
+	FlexWrap       Wrap             `json:"flexWrap"`
+	Overflow       Overflow         `json:"overflow"`
+	Display        Display          `json:"display"`
+	BoxSizing      BoxSizing        `json:"boxSizing"`
+	Flex           jsonFloat        `json:"flex"`
+	FlexGrow       jsonFloat        `json:"flexGrow"`
+	FlexShrink     jsonFloat        `json:"flexShrink"`
+	FlexBasis      Value            `json:"flexBasis"`
+	Width          Value            `json:"width"`
+	Height         Value            `json:"height"`
+	MinWidth       Value            `json:"minWidth"`
+	MinHeight      Value            `json:"minHeight"`
+	MaxWidth       Value            `json:"maxWidth"`
+	MaxHeight      Value            `json:"maxHeight"`
+	Margin         map[string]Value `json:"margin,omitempty"`
+	Position       map[string]Value `json:"position,omitempty"`
+	Padding        map[string]Value `json:"padding,omitempty"`
+	Border         map[string]Value `json:"border,omitempty"`
+	Gap            map[string]Value `json:"gap,omitempty"`
+	FontSize       Value            `json:"fontSize"`
+	PointerEvents  PointerEvents    `json:"pointerEvents"`
+	AspectRatio    jsonFloat        `json:"aspectRatio"`
+	// CustomProperties maps names like "--spacing" to values
+	CustomProperties map[string]Value `json:"customProperties,omitempty"`
+}
+
+type jsonLayout struct {
+	Left        jsonFloat            `json:"left"`
+	Top         jsonFloat            `json:"top"`
+	Right       jsonFloat            `json:"right"`
+	Bottom      jsonFloat            `json:"bottom"`
+	Width       jsonFloat            `json:"width"`
+	Height      jsonFloat            `json:"height"`
+	Margin      map[string]jsonFloat `json:"margin"`
+	Border      map[string]jsonFloat `json:"border"`
+	Padding     map[string]jsonFloat `json:"padding"`
+	Direction   Direction            `json:"direction"`
+	HadOverflow bool                 `json:"hadOverflow"`
+}
+
+type jsonNode struct {
+	ID           string      `json:"id,omitempty"`
+	Tag          string      `json:"tag,omitempty"`
+	Classes      []string    `json:"classes,omitempty"`
+	NodeType     NodeType    `json:"nodeType"`
+	IsDirty      bool        `json:"isDirty"`
+	HasNewLayout bool        `json:"hasNewLayout"`
+	Style        jsonStyle   `json:"style"`
+	Layout       jsonLayout  `json:"layout"`
+	Children     []*jsonNode `json:"children,omitempty"`
+}
+
+// jsonDefaults is a new node in JSON. nodeDefaults has no calc values, so
+// there is no error.
+var jsonDefaults, _ = nodeToJSON(&nodeDefaults)
+
+// UnmarshalJSON uses the defaults of a new node for missing fields
+func (j *jsonNode) UnmarshalJSON(data []byte) error {
+	type plainNode jsonNode
+	*j = *jsonDefaults
+	// json.Unmarshal adds to existing maps, so they can't be shared
+	j.Layout.Margin = copyLayoutEdges(jsonDefaults.Layout.Margin)
+	j.Layout.Border = copyLayoutEdges(jsonDefaults.Layout.Border)
+	j.Layout.Padding = copyLayoutEdges(jsonDefaults.Layout.Padding)
+	return json.Unmarshal(data, (*plainNode)(j))
+}
+
+// edgesToJSON returns the defined values of edges keyed by edge name
+func edgesToJSON(edges []Value) map[string]Value {
+	m := map[string]Value{}
+	for edge, v := range edges {
+		if v.Unit != UnitUndefined {
+			m[EdgeToString(Edge(edge))] = v
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func gapToJSON(gap []Value) map[string]Value {
+	m := map[string]Value{}
+	for gutter, v := range gap {
+		if v.Unit != UnitUndefined {
+			m[GutterToString(Gutter(gutter))] = v
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+func layoutEdgesToJSON(edges []float32) map[string]jsonFloat {
+	m := map[string]jsonFloat{}
+	for edge, v := range edges {
+		m[EdgeToString(Edge(edge))] = jsonFloat(v)
+	}
+	return m
+}
+
+func copyLayoutEdges(m map[string]jsonFloat) map[string]jsonFloat {
+	c := make(map[string]jsonFloat, len(m))
+	for name, v := range m {
+		c[name] = v
+	}
+	return c
+}
+
+// edgeFromJSON returns the index of an edge or gutter named name
+func edgeFromJSON(name string, count int, toString func(int) string) (int, error) {
+	for i := 0; i < count; i++ {
+		if toString(i) == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("flex: invalid edge %q", name)
+}
+
+func edgeName(i int) string   { return EdgeToString(Edge(i)) }
+func gutterName(i int) string { return GutterToString(Gutter(i)) }
+
+func valuesFromJSON(dst []Value, m map[string]Value, toString func(int) string) error {
+	for i := range dst {
+		dst[i] = ValueUndefined
+	}
+	for name, v := range m {
+		i, err := edgeFromJSON(name, len(dst), toString)
+		if err != nil {
+			return err
+		}
+		dst[i] = v
+	}
+	return nil
+}
+
+func floatsFromJSON(dst []float32, m map[string]jsonFloat) error {
+	for i := range dst {
+		dst[i] = Undefined
+	}
+	for name, v := range m {
+		i, err := edgeFromJSON(name, len(dst), edgeName)
+		if err != nil {
+			return err
+		}
+		dst[i] = float32(v)
+	}
+	return nil
+}
+
+func nodeToJSON(node *Node) (*jsonNode, error) {
+	style := &node.Style
+	layout := &node.Layout
+	j := &jsonNode{
+		ID:           node.id,
+		Tag:          node.tag,
+		Classes:      node.classes,
+		NodeType:     node.NodeType,
+		IsDirty:      node.IsDirty,
+		HasNewLayout: node.hasNewLayout,
+		Style: jsonStyle{
+			Direction:        style.Direction,
+			FlexDirection:    style.FlexDirection,
+			JustifyContent:   style.JustifyContent,
+			AlignContent:     style.AlignContent,
+			AlignItems:       style.AlignItems,
+			AlignSelf:        style.AlignSelf,
+			PositionType:     style.PositionType,
+			FlexWrap:         style.FlexWrap,
+			Overflow:         style.Overflow,
+			Display:          style.Display,
+			BoxSizing:        style.BoxSizing,
+			Flex:             jsonFloat(style.Flex),
+			FlexGrow:         jsonFloat(style.FlexGrow),
+			FlexShrink:       jsonFloat(style.FlexShrink),
+			FlexBasis:        style.FlexBasis,
+			Width:            style.Dimensions[DimensionWidth],
+			Height:           style.Dimensions[DimensionHeight],
+			MinWidth:         style.MinDimensions[DimensionWidth],
+			MinHeight:        style.MinDimensions[DimensionHeight],
+			MaxWidth:         style.MaxDimensions[DimensionWidth],
+			MaxHeight:        style.MaxDimensions[DimensionHeight],
+			Margin:           edgesToJSON(style.Margin[:]),
+			Position:         edgesToJSON(style.Position[:]),
+			Padding:          edgesToJSON(style.Padding[:]),
+			Border:           edgesToJSON(style.Border[:]),
+			Gap:              gapToJSON(style.Gap[:]),
+			FontSize:         style.FontSize,
+			PointerEvents:    style.PointerEvents,
+			AspectRatio:      jsonFloat(style.AspectRatio),
+			CustomProperties: style.CustomProperties,
+		},
+		Layout: jsonLayout{
+			Left:        jsonFloat(layout.Position[EdgeLeft]),
+			Top:         jsonFloat(layout.Position[EdgeTop]),
+			Right:       jsonFloat(layout.Position[EdgeRight]),
+			Bottom:      jsonFloat(layout.Position[EdgeBottom]),
+			Width:       jsonFloat(layout.Dimensions[DimensionWidth]),
+			Height:      jsonFloat(layout.Dimensions[DimensionHeight]),
+			Margin:      layoutEdgesToJSON(layout.Margin[:]),
+			Border:      layoutEdgesToJSON(layout.Border[:]),
+			Padding:     layoutEdgesToJSON(layout.Padding[:]),
+			Direction:   layout.Direction,
+			HadOverflow: layout.HadOverflow,
+		},
+	}
+	for _, child := range node.Children {
+		jc, err := nodeToJSON(child)
+		if err != nil {
+			return nil, err
+		}
+		j.Children = append(j.Children, jc)
+	}
+	return j, nil
+}
+
+// nodeFromJSON sets style, layout and state of node and creates its children
+func nodeFromJSON(node *Node, j *jsonNode) error {
+	s := &j.Style
+	style := &node.Style
+	style.Direction = s.Direction
+	style.FlexDirection = s.FlexDirection
+	style.JustifyContent = s.JustifyContent
+	style.AlignContent = s.AlignContent
+	style.AlignItems = s.AlignItems
+	style.AlignSelf = s.AlignSelf
+	style.PositionType = s.PositionType
+	style.FlexWrap = s.FlexWrap
+	style.Overflow = s.Overflow
+	style.Display = s.Display
+	style.BoxSizing = s.BoxSizing
+	style.Flex = float32(s.Flex)
+	style.FlexGrow = float32(s.FlexGrow)
+	style.FlexShrink = float32(s.FlexShrink)
+	style.FlexBasis = s.FlexBasis
+	style.Dimensions[DimensionWidth] = s.Width
+	style.Dimensions[DimensionHeight] = s.Height
+	style.MinDimensions[DimensionWidth] = s.MinWidth
+	style.MinDimensions[DimensionHeight] = s.MinHeight
+	style.MaxDimensions[DimensionWidth] = s.MaxWidth
+	style.MaxDimensions[DimensionHeight] = s.MaxHeight
+	style.FontSize = s.FontSize
+	style.PointerEvents = s.PointerEvents
+	style.AspectRatio = float32(s.AspectRatio)
+	style.CustomProperties = nil
+	if len(s.CustomProperties) > 0 {
+		style.CustomProperties = s.CustomProperties
+	}
+	for _, edges := range []struct {
+		dst []Value
+		src map[string]Value
+	}{
+		{style.Margin[:], s.Margin},
+		{style.Position[:], s.Position},
+		{style.Padding[:], s.Padding},
+		{style.Border[:], s.Border},
+	} {
+		if err := valuesFromJSON(edges.dst, edges.src, edgeName); err != nil {
+			return err
+		}
+	}
+	if err := valuesFromJSON(style.Gap[:], s.Gap, gutterName); err != nil {
+		return err
+	}
+
+	l := &j.Layout
+	layout := &node.Layout
+	layout.Position[EdgeLeft] = float32(l.Left)
+	layout.Position[EdgeTop] = float32(l.Top)
+	layout.Position[EdgeRight] = float32(l.Right)
+	layout.Position[EdgeBottom] = float32(l.Bottom)
+	layout.Dimensions[DimensionWidth] = float32(l.Width)
+	layout.Dimensions[DimensionHeight] = float32(l.Height)
+	layout.measuredDimensions = layout.Dimensions
+	for _, edges := range []struct {
+		dst []float32
+		src map[string]jsonFloat
+	}{
+		{layout.Margin[:], l.Margin},
+		{layout.Border[:], l.Border},
+		{layout.Padding[:], l.Padding},
+	} {
+		if err := floatsFromJSON(edges.dst, edges.src); err != nil {
+			return err
+		}
+	}
+	layout.Direction = l.Direction
+	layout.HadOverflow = l.HadOverflow
+
+	node.id = j.ID
+	node.tag = j.Tag
+	node.classes = j.Classes
+	node.NodeType = j.NodeType
+	node.IsDirty = j.IsDirty
+	node.hasNewLayout = j.HasNewLayout
+
+	for _, jc := range j.Children {
+		child := NewNodeWithConfig(node.Config)
+		if err := nodeFromJSON(child, jc); err != nil {
+			return err
+		}
+		node.Children = append(node.Children, child)
+		child.Parent = node
+	}
+	return nil
+}
+
+// MarshalJSON returns node and its children as JSON, including style,
+// layout and dirty state. Measure, baseline and print functions, Context and
+// Config are not included.
+func (node *Node) MarshalJSON() ([]byte, error) {
+	j, err := nodeToJSON(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON replaces the style, layout and children of node with a tree
+// marshaled with MarshalJSON. The children get node's Config, or the default
+// config if node has none. Parent links are rebuilt.
+func (node *Node) UnmarshalJSON(data []byte) error {
+	var j jsonNode
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if node.Config == nil {
+		node.Config = &configDefaults
+	}
+	// check for errors first so node is unchanged if data is invalid
+	if err := nodeFromJSON(NewNodeWithConfig(node.Config), &j); err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		child.Parent = nil
+	}
+	node.Children = nil
+	node.Layout = nodeDefaults.Layout
+	node.resolvedDimensions = nodeDefaults.resolvedDimensions
+	return nodeFromJSON(node, &j)
+}
@@ -0,0 +1,171 @@
+package flex
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON_round_trip(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceAround)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(300)
+	root.StyleSetHeight(200)
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetBorder(EdgeLeft, 2)
+	root.StyleSetGap(GutterColumn, 5)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetFlexBasisPercent(30)
+	rootChild0.StyleSetMargin(EdgeTop, 4)
+	rootChild0.StyleSetMinWidthCalc(CalcSub(CalcPercent(20), CalcPoint(3)))
+	rootChild0.StyleSetAspectRatio(2)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1.StyleSetPositionPercent(EdgeRight, 10)
+	rootChild1.StyleSetWidthMaxContent()
+	rootChild1.StyleSetHeightValue(Value{Value: 1.5, Unit: UnitEm})
+	rootChild1.StyleSetMaxHeightCalc(CalcPoint(40))
+	root.InsertChild(rootChild1, 1)
+
+	rootChild1Child0 := NewNodeWithConfig(config)
+	rootChild1Child0.StyleSetWidth(33.3)
+	rootChild1Child0.StyleSetHeight(10)
+	rootChild1.InsertChild(rootChild1Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+	rootChild0.StyleSetFlexShrink(0.25)
+
+	data, err := json.Marshal(root)
+	assert.Equal(t, nil, err)
+
+	parsed := NewNodeWithConfig(NewConfig())
+	assert.Equal(t, nil, json.Unmarshal(data, parsed))
+	assert.Equal(t, nil, ValidateTree(parsed))
+	assert.True(t, parsed.Children[1].Children[0].Config == parsed.Config)
+	assertSameLayout(t, root, parsed)
+	assert.True(t, parsed.IsDirty)
+	assert.False(t, parsed.Children[1].IsDirty)
+
+	data2, err := json.Marshal(parsed)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, string(data), string(data2))
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+	CalculateLayout(parsed, Undefined, Undefined, DirectionRTL)
+	assertSameLayout(t, root, parsed)
+}
+
+func TestJSON_readable(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceAround)
+	root.StyleSetHeight(200)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexBasisPercent(30)
+	rootChild0.StyleSetMargin(EdgeTop, 4)
+	rootChild0.StyleSetMinWidthCalc(CalcSub(CalcPercent(20), CalcPoint(3)))
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1.StyleSetWidthMaxContent()
+	rootChild1.StyleSetHeightValue(Value{Value: 1.5, Unit: UnitEm})
+	rootChild1.StyleSetMaxHeightCalc(CalcPoint(40))
+	root.InsertChild(rootChild1, 1)
+
+	rootChild1Child0 := NewNodeWithConfig(config)
+	rootChild1Child0.StyleSetWidth(33.3)
+	rootChild1.InsertChild(rootChild1Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	data, err := json.Marshal(root)
+	assert.Equal(t, nil, err)
+	s := string(data)
+	for _, exp := range []string{
+		`"flexDirection":"row"`,
+		`"justifyContent":"space-around"`,
+		`"height":"200px"`,
+		`"flexBasis":"30%"`,
+		`"flex":"undefined"`,
+		`"margin":{"top":"4px"}`,
+		`"minWidth":"calc(20% - 3px)"`,
+		`"maxHeight":"calc(40px)"`,
+		`"width":"max-content"`,
+		`"height":"1.5em"`,
+		`"width":"33.3px"`,
+		`"padding":{"all":"10px"}`,
+		`"positionType":"absolute"`,
+		`"direction":"rtl"`,
+	} {
+		assert.True(t, strings.Contains(s, exp), exp)
+	}
+	assert.False(t, strings.Contains(s, "Parent"))
+}
+
+func TestJSON_defaults_for_missing_fields(t *testing.T) {
+	var node Node
+	err := json.Unmarshal([]byte(`{
+		"style": {"width": "50%", "minHeight": "undefined"},
+		"layout": {"margin": {"left": 5}},
+		"children": [{}]
+	}`), &node)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Value{Value: 50, Unit: UnitPercent}, node.StyleGetWidth())
+	assert.True(t, valueEq(ValueAuto, node.StyleGetHeight()))
+	assert.True(t, valueEq(ValueUndefined, node.Style.MinDimensions[DimensionHeight]))
+	assertFloatEqual(t, 5, node.Layout.Margin[EdgeLeft])
+	assert.True(t, FloatsEqual(nodeDefaults.Layout.Margin[EdgeLeft], node.Children[0].Layout.Margin[EdgeLeft]))
+	assert.True(t, node.Config != nil)
+	assert.Equal(t, 1, len(node.Children))
+	assert.True(t, styleEq(&nodeDefaults.Style, &node.Children[0].Style))
+	assert.True(t, node.Children[0].Parent == &node)
+}
+
+func TestJSON_invalid(t *testing.T) {
+	node := NewNode()
+	node.StyleSetWidth(10)
+	child := NewNode()
+	node.InsertChild(child, 0)
+
+	for _, data := range []string{
+		`{"style": {"width": "10pt"}}`,
+		`{"style": {"flexDirection": "sideways"}}`,
+		`{"style": {"margin": {"middle": "1px"}}}`,
+		`{"style": {"flexGrow": "a lot"}}`,
+	} {
+		assert.True(t, json.Unmarshal([]byte(data), node) != nil, data)
+	}
+	assert.Equal(t, Value{Value: 10, Unit: UnitPoint}, node.StyleGetWidth())
+	assert.True(t, child.Parent == node)
+}
+
+func TestJSON_selector_attributes(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.SetTag("section")
+	child := NewNodeWithConfig(config)
+	child.SetID("main")
+	child.SetClasses("a", "b")
+	root.InsertChild(child, 0)
+
+	data, err := json.Marshal(root)
+	assert.Equal(t, nil, err)
+	assert.True(t, strings.Contains(string(data), `"id":"main","classes":["a","b"]`))
+
+	parsed := NewNodeWithConfig(NewConfig())
+	assert.Equal(t, nil, json.Unmarshal(data, parsed))
+	assert.Equal(t, "section", parsed.Tag())
+	assert.Equal(t, "main", parsed.Children[0].ID())
+	assert.Equal(t, []string{"a", "b"}, parsed.Children[0].Classes())
+}
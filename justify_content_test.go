@@ -326,6 +326,71 @@ func TestJustify_content_row_space_around(t *testing.T) {
 	assertFloatEqual(t, 102, rootChild2.LayoutGetHeight())
 }
 
+func TestJustify_content_row_space_evenly(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceEvenly)
+	root.StyleSetWidth(102)
+	root.StyleSetHeight(102)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(10)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 102, root.LayoutGetWidth())
+	assertFloatEqual(t, 102, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 18, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 46, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 74, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild2.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 102, root.LayoutGetWidth())
+	assertFloatEqual(t, 102, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 74, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 46, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 18, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 102, rootChild2.LayoutGetHeight())
+}
+
 func TestJustify_content_column_flex_start(t *testing.T) {
 	config := NewConfig()
 
@@ -643,3 +708,67 @@ func TestJustify_content_column_space_around(t *testing.T) {
 	assertFloatEqual(t, 102, rootChild2.LayoutGetWidth())
 	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
 }
+
+func TestJustify_content_column_space_evenly(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetJustifyContent(JustifySpaceEvenly)
+	root.StyleSetWidth(102)
+	root.StyleSetHeight(102)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetHeight(10)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 102, root.LayoutGetWidth())
+	assertFloatEqual(t, 102, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 18, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 46, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 74, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 102, root.LayoutGetWidth())
+	assertFloatEqual(t, 102, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 18, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 46, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 74, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 102, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+}
@@ -0,0 +1,106 @@
+package flex
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLog struct {
+	levels []LogLevel
+	buf    strings.Builder
+}
+
+func (l *testLog) logger(config *Config, node *Node, level LogLevel, format string, args ...interface{}) int {
+	l.levels = append(l.levels, level)
+	n, _ := fmt.Fprintf(&l.buf, format, args...)
+	return n
+}
+
+func TestLogging_node_print_uses_config_logger(t *testing.T) {
+	var l testLog
+	config := NewConfig()
+	config.Logger = l.logger
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.InsertChild(NewNodeWithConfig(config), 0)
+	NodePrint(root, PrintOptionsStyle|PrintOptionsChildren)
+
+	out := l.buf.String()
+	assert.True(t, strings.HasPrefix(out, "<div style=\"width: 100px; \" >"))
+	assert.True(t, strings.HasSuffix(out, "</div>\n"))
+	for _, level := range l.levels {
+		assert.Equal(t, LogLevelDebug, level)
+	}
+}
+
+func TestLogging_level_filtering(t *testing.T) {
+	var l testLog
+	config := NewConfig()
+	config.Logger = l.logger
+	config.LogLevel = LogLevelInfo
+
+	root := NewNodeWithConfig(config)
+	NodePrint(root, PrintOptionsStyle)
+	assert.Equal(t, 0, len(l.levels))
+
+	log(root, LogLevelWarn, "warn\n")
+	log(root, LogLevelFatal, "fatal\n")
+	assert.Equal(t, "warn\nfatal\n", l.buf.String())
+}
+
+func TestLogging_print_changes(t *testing.T) {
+	var l testLog
+	config := NewConfig()
+	config.Logger = l.logger
+	config.PrintChanges = true
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.True(t, strings.Contains(l.buf.String(), "wm: "))
+	for _, level := range l.levels {
+		assert.Equal(t, LogLevelVerbose, level)
+	}
+
+	l = testLog{}
+	config.LogLevel = LogLevelDebug
+	root.StyleSetWidth(50)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, "", l.buf.String())
+}
+
+func TestLogging_nil_logger(t *testing.T) {
+	config := NewConfig()
+	config.Logger = nil
+	root := NewNodeWithConfig(config)
+	NodePrint(root, PrintOptionsStyle)
+}
+
+func TestLogging_slog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	config := NewConfig()
+	config.Logger = NewSlogLogger(slog.New(handler))
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.InsertChild(NewNodeWithConfig(config), 0)
+	NodePrint(root, PrintOptionsStyle|PrintOptionsChildren)
+	log(root, LogLevelError, "broken %s\n", "tree")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.True(t, strings.Contains(lines[0], "level=DEBUG"))
+	assert.True(t, strings.Contains(lines[0], `msg="<div style=\"width: 100px; \" >"`))
+	assert.True(t, strings.Contains(lines[0], "flex.level=debug"))
+	assert.True(t, strings.Contains(lines[3], "level=ERROR"))
+	assert.True(t, strings.Contains(lines[3], `msg="broken tree"`))
+}
@@ -0,0 +1,55 @@
+package flex
+
+import "sync"
+
+// parallelLayoutChild is a child whose layout has been deferred by
+// nodelayoutImpl because its size is already fully determined.
+type parallelLayoutChild struct {
+	node   *Node
+	width  float32
+	height float32
+}
+
+// layoutChildrenParallel lays out children on up to Config.ParallelWorkers
+// goroutines. If no worker is free, the child is laid out on the calling
+// goroutine, so nested calls never block waiting for a worker.
+// The subtrees of children are disjoint, so the result is identical to laying
+// them out one after another.
+func layoutChildrenParallel(children []parallelLayoutChild, direction Direction,
+	parentWidth float32, parentHeight float32, config *Config, ctx *layoutContext) {
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicValue interface{}
+
+	for i := range children {
+		child := &children[i]
+		select {
+		case ctx.workers <- struct{}{}:
+			wg.Add(1)
+			// each goroutine tracks its own recursion depth
+			workerCtx := *ctx
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						panicOnce.Do(func() { panicValue = r })
+					}
+					<-ctx.workers
+					wg.Done()
+				}()
+				layoutNodeInternal(child.node, child.width, child.height, direction,
+					MeasureModeExactly, MeasureModeExactly, parentWidth, parentHeight,
+					true, "flex", config, &workerCtx)
+			}()
+		default:
+			layoutNodeInternal(child.node, child.width, child.height, direction,
+				MeasureModeExactly, MeasureModeExactly, parentWidth, parentHeight,
+				true, "flex", config, ctx)
+		}
+	}
+	wg.Wait()
+
+	// re-raise assertion failures from workers on the calling goroutine
+	if panicValue != nil {
+		panic(panicValue)
+	}
+}
@@ -0,0 +1,122 @@
+package flex
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+// newDashboard creates a wrapping row of fixed-size panels, each with a
+// column of flexible rows inside.
+func newDashboard(config *Config, panelCount int, rowCount int) *Node {
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(1000)
+
+	for i := 0; i < panelCount; i++ {
+		panel := NewNodeWithConfig(config)
+		panel.StyleSetWidth(float32(150 + i%7))
+		panel.StyleSetHeight(200)
+		panel.StyleSetPadding(EdgeAll, 3)
+		for j := 0; j < rowCount; j++ {
+			row := NewNodeWithConfig(config)
+			row.StyleSetFlexDirection(FlexDirectionRow)
+			row.StyleSetFlexGrow(1)
+			row.StyleSetMargin(EdgeBottom, 1)
+			for k := 0; k < 3; k++ {
+				cell := NewNodeWithConfig(config)
+				cell.StyleSetFlexGrow(float32(k + 1))
+				cell.StyleSetMinWidth(10)
+				row.InsertChild(cell, k)
+			}
+			panel.InsertChild(row, j)
+		}
+		root.InsertChild(panel, i)
+	}
+	return root
+}
+
+// assertSameLayout fails unless got and its descendants have a layout
+// bit-identical to exp and its descendants
+func assertSameLayout(t *testing.T, exp *Node, got *Node) {
+	same := func(a []float32, b []float32) bool {
+		for i := range a {
+			if math.Float32bits(a[i]) != math.Float32bits(b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if !same(exp.Layout.Position[:], got.Layout.Position[:]) ||
+		!same(exp.Layout.Dimensions[:], got.Layout.Dimensions[:]) ||
+		!same(exp.Layout.Margin[:], got.Layout.Margin[:]) ||
+		!same(exp.Layout.Border[:], got.Layout.Border[:]) ||
+		!same(exp.Layout.Padding[:], got.Layout.Padding[:]) ||
+		exp.Layout.Direction != got.Layout.Direction ||
+		len(exp.Children) != len(got.Children) {
+		t.Fatalf("layout differs: exp %v %v, got %v %v",
+			exp.Layout.Position, exp.Layout.Dimensions,
+			got.Layout.Position, got.Layout.Dimensions)
+	}
+	for i, child := range exp.Children {
+		assertSameLayout(t, child, got.Children[i])
+	}
+}
+
+func TestParallel_layout_is_identical_to_sequential(t *testing.T) {
+	config := NewConfig()
+	parallelConfig := NewConfig()
+	parallelConfig.ParallelWorkers = 4
+
+	sequential := newDashboard(config, 40, 10)
+	parallel := newDashboard(parallelConfig, 40, 10)
+
+	CalculateLayout(sequential, Undefined, Undefined, DirectionLTR)
+	CalculateLayout(parallel, Undefined, Undefined, DirectionLTR)
+	assertSameLayout(t, sequential, parallel)
+
+	CalculateLayout(sequential, Undefined, Undefined, DirectionRTL)
+	CalculateLayout(parallel, Undefined, Undefined, DirectionRTL)
+	assertSameLayout(t, sequential, parallel)
+
+	sequential.GetChild(3).StyleSetWidth(400)
+	parallel.GetChild(3).StyleSetWidth(400)
+	CalculateLayout(sequential, Undefined, Undefined, DirectionLTR)
+	CalculateLayout(parallel, Undefined, Undefined, DirectionLTR)
+	assertSameLayout(t, sequential, parallel)
+}
+
+func benchmarkDashboardLayout(b *testing.B, workers int) {
+	config := NewConfig()
+	config.ParallelWorkers = workers
+	root := newDashboard(config, 64, 50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		markLeavesDirty(root)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	}
+}
+
+// markLeavesDirty forces a full relayout of the tree
+func markLeavesDirty(node *Node) {
+	if len(node.Children) == 0 {
+		nodeMarkDirtyInternal(node)
+	}
+	for _, child := range node.Children {
+		markLeavesDirty(child)
+	}
+}
+
+func BenchmarkLayout_sequential(b *testing.B) {
+	benchmarkDashboardLayout(b, 0)
+}
+
+func BenchmarkLayout_parallel(b *testing.B) {
+	// with a single CPU the workers run one after another
+	if runtime.NumCPU() < 2 {
+		b.Skip("parallel layout needs more than one CPU")
+	}
+	benchmarkDashboardLayout(b, 4)
+}
@@ -0,0 +1,150 @@
+package flex
+
+import "testing"
+
+func TestPosition_static_ignores_insets(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0.StyleSetPosition(EdgeLeft, 10)
+	rootChild0.StyleSetPosition(EdgeTop, 10)
+	rootChild0.StyleSetWidth(50)
+	rootChild0.StyleSetHeight(50)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 50, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetHeight())
+}
+
+func TestPosition_static_absolute_child_uses_containing_block(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetBorder(EdgeAll, 5)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0.StyleSetMargin(EdgeTop, 30)
+	rootChild0.StyleSetWidth(100)
+	rootChild0.StyleSetHeight(100)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0Child0.StyleSetPosition(EdgeEnd, 0)
+	rootChild0Child0.StyleSetPosition(EdgeBottom, 0)
+	rootChild0Child0.StyleSetWidth(10)
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 5, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 35, rootChild0.LayoutGetTop())
+
+	assertFloatEqual(t, 180, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 150, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 95, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 35, rootChild0.LayoutGetTop())
+
+	assertFloatEqual(t, -90, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 150, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetHeight())
+}
+
+func TestPosition_static_nearest_relative_ancestor_wins(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetMargin(EdgeLeft, 20)
+	rootChild0.StyleSetMargin(EdgeTop, 20)
+	rootChild0.StyleSetWidth(150)
+	rootChild0.StyleSetHeight(150)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0Child0.StyleSetPadding(EdgeAll, 10)
+	rootChild0Child0.StyleSetHeight(50)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0Child0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0Child0Child0.StyleSetHeight(20)
+	rootChild0Child0.InsertChild(rootChild0Child0Child0, 0)
+
+	rootChild0Child0Child0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0Child0Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0Child0Child0Child0.StyleSetPosition(EdgeLeft, 0)
+	rootChild0Child0Child0Child0.StyleSetPosition(EdgeTop, 100)
+	rootChild0Child0Child0Child0.StyleSetWidthPercent(50)
+	rootChild0Child0Child0Child0.StyleSetHeight(10)
+	rootChild0Child0Child0.InsertChild(rootChild0Child0Child0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 10, rootChild0Child0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 10, rootChild0Child0Child0.LayoutGetTop())
+
+	assertFloatEqual(t, -10, rootChild0Child0Child0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 90, rootChild0Child0Child0Child0.LayoutGetTop())
+	assertFloatEqual(t, 75, rootChild0Child0Child0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0Child0Child0.LayoutGetHeight())
+}
+
+func TestPosition_static_absolute_child_keeps_static_position(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetPositionType(PositionTypeStatic)
+	rootChild1.StyleSetPadding(EdgeAll, 10)
+	rootChild1.StyleSetWidth(100)
+	rootChild1.StyleSetHeight(100)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild1Child0 := NewNodeWithConfig(config)
+	rootChild1Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1Child0.StyleSetPosition(EdgeLeft, 5)
+	rootChild1Child0.StyleSetWidth(10)
+	rootChild1Child0.StyleSetHeight(10)
+	rootChild1.InsertChild(rootChild1Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+
+	assertFloatEqual(t, -45, rootChild1Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1Child0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild1Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild1Child0.LayoutGetHeight())
+}
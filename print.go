@@ -1,47 +1,79 @@
 package flex
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
 
-func indent(node *Node, n int) {
-	for i := 0; i < n; i++ {
-		log(node, LogLevelDebug, "  ")
+// printDecl is a single "name: value" pair of printed style or layout
+type printDecl struct {
+	name  string
+	value string
+}
+
+// valueToString returns CSS text of value or "" if value is undefined
+func valueToString(value *Value) string {
+	switch value.Unit {
+	case UnitUndefined:
+		return ""
+	case UnitAuto, UnitMinContent, UnitMaxContent:
+		return UnitToString(value.Unit)
+	case UnitCalc:
+		return value.Calc.String()
+	case UnitFitContent:
+		if FloatIsUndefined(value.Value) {
+			return "fit-content"
+		}
+		return fmt.Sprintf("fit-content(%gpx)", value.Value)
+	}
+	return fmt.Sprintf("%g%s", value.Value, unitSuffix(value.Unit))
+}
+
+// unitSuffix returns the CSS suffix of a value in unit
+func unitSuffix(unit Unit) string {
+	switch unit {
+	case UnitPoint:
+		return "px"
+	case UnitPercent:
+		return "%"
 	}
+	return UnitToString(unit)
 }
 
-func printNumberIfNotUndefinedf(node *Node, str string, number float32) {
+func appendNumberIfNotUndefinedf(decls []printDecl, name string, number float32) []printDecl {
 	if !FloatIsUndefined(number) {
-		log(node, LogLevelDebug, "%s: %g; ", str, number)
+		decls = append(decls, printDecl{name, fmt.Sprintf("%g", number)})
 	}
+	return decls
 }
 
-func printNumberIfNotUndefined(node *Node, str string, number *Value) {
+func appendNumberIfNotUndefined(decls []printDecl, name string, number *Value) []printDecl {
 	if number.Unit != UnitUndefined {
-		if number.Unit == UnitAuto {
-			log(node, LogLevelDebug, "%s: auto; ", str)
-		} else {
-			unit := "%"
-
-			if number.Unit == UnitPoint {
-				unit = "px"
-			}
-			log(node, LogLevelDebug, "%s: %g%s; ", str, number.Value, unit)
-		}
+		decls = append(decls, printDecl{name, valueToString(number)})
 	}
+	return decls
 }
-func printNumberIfNotAuto(node *Node, str string, number *Value) {
+
+func appendNumberIfNotAuto(decls []printDecl, name string, number *Value) []printDecl {
 	if number.Unit != UnitAuto {
-		printNumberIfNotUndefined(node, str, number)
+		decls = appendNumberIfNotUndefined(decls, name, number)
 	}
+	return decls
 }
 
-func printEdgeIfNotUndefined(node *Node, str string, edges []Value, edge Edge) {
-	printNumberIfNotUndefined(node, str, computedEdgeValue(edges, edge, &ValueUndefined))
+func appendEdgeIfNotUndefined(decls []printDecl, name string, edges []Value, edge Edge) []printDecl {
+	return appendNumberIfNotUndefined(decls, name, computedEdgeValue(edges, edge, &ValueUndefined))
 }
 
-func printNumberIfNotZero(node *Node, str string, number *Value) {
-	if !FloatsEqual(number.Value, 0) {
-		printNumberIfNotUndefined(node, str, number)
+func appendNumberIfNotZero(decls []printDecl, name string, number *Value) []printDecl {
+	if !FloatsEqual(number.Value, 0) || number.Unit == UnitCalc {
+		decls = appendNumberIfNotUndefined(decls, name, number)
 	}
+	return decls
 }
 
 func fourValuesEqual(four []Value) bool {
@@ -49,118 +81,354 @@ func fourValuesEqual(four []Value) bool {
 		ValueEqual(four[0], four[3])
 }
 
-func printEdges(node *Node, str string, edges []Value) {
-	if fourValuesEqual(edges) {
-		printNumberIfNotZero(node, str, &edges[EdgeLeft])
-	} else {
+// appendEdges appends the edges of a margin, padding or border. The HTML
+// format prints them like NodePrint always did: only the shorthand if the
+// four physical edges are equal, else every edge by its name. The other
+// formats print the shorthand first so the edges written after it override
+// it, which StyleSetCSS relies on.
+func appendEdges(decls []printDecl, name string, edges []Value, format PrintFormat) []printDecl {
+	if format == PrintFormatHTML {
+		if fourValuesEqual(edges) {
+			return appendNumberIfNotZero(decls, name, &edges[EdgeLeft])
+		}
 		for edge := EdgeLeft; edge < EdgeCount; edge++ {
-			buf := fmt.Sprintf("%s-%s", str, EdgeToString(edge))
-			printNumberIfNotZero(node, buf, &edges[edge])
+			decls = appendNumberIfNotZero(decls, name+"-"+EdgeToString(edge), &edges[edge])
 		}
+		return decls
 	}
-}
 
-func nodePrintInternal(node *Node, options PrintOptions, level int) {
-	indent(node, level)
-	log(node, LogLevelDebug, "<div ")
+	if fourValuesEqual(edges) && edges[EdgeAll].Unit == UnitUndefined {
+		decls = appendNumberIfNotZero(decls, name, &edges[EdgeLeft])
+		for edge := EdgeStart; edge < EdgeAll; edge++ {
+			decls = appendNumberIfNotZero(decls, name+"-"+EdgeToString(edge), &edges[edge])
+		}
+		return decls
+	}
+	decls = appendNumberIfNotZero(decls, name, &edges[EdgeAll])
+	for edge := EdgeLeft; edge < EdgeAll; edge++ {
+		decls = appendNumberIfNotZero(decls, name+"-"+EdgeToString(edge), &edges[edge])
+	}
+	return decls
+}
 
-	if node.Print != nil {
-		node.Print(node)
+// nodeStyleDecls returns style properties of node which differ from defaults.
+// The properties NodePrint printed before there were several formats keep
+// their order and, in the HTML format, their names.
+func nodeStyleDecls(node *Node, format PrintFormat) []printDecl {
+	var decls []printDecl
+	style := &node.Style
+	names := make([]string, 0, len(style.CustomProperties))
+	for name := range style.CustomProperties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := style.CustomProperties[name]
+		decls = append(decls, printDecl{name, valueToString(&value)})
+	}
+	if format != PrintFormatHTML && style.Direction != nodeDefaults.Style.Direction {
+		decls = append(decls, printDecl{"direction", DirectionToString(style.Direction)})
+	}
+	if style.FlexDirection != nodeDefaults.Style.FlexDirection {
+		decls = append(decls, printDecl{"flex-direction", FlexDirectionToString(style.FlexDirection)})
+	}
+	if style.JustifyContent != nodeDefaults.Style.JustifyContent {
+		decls = append(decls, printDecl{"justify-content", JustifyToString(style.JustifyContent)})
+	}
+	if style.AlignItems != nodeDefaults.Style.AlignItems {
+		decls = append(decls, printDecl{"align-items", AlignToString(style.AlignItems)})
+	}
+	if style.AlignContent != nodeDefaults.Style.AlignContent {
+		decls = append(decls, printDecl{"align-content", AlignToString(style.AlignContent)})
+	}
+	if style.AlignSelf != nodeDefaults.Style.AlignSelf {
+		decls = append(decls, printDecl{"align-self", AlignToString(style.AlignSelf)})
 	}
 
-	if options&PrintOptionsLayout != 0 {
-		log(node, LogLevelDebug, "layout=\"")
-		log(node, LogLevelDebug, "width: %g; ", node.Layout.Dimensions[DimensionWidth])
-		log(node, LogLevelDebug, "height: %g; ", node.Layout.Dimensions[DimensionHeight])
-		log(node, LogLevelDebug, "top: %g; ", node.Layout.Position[EdgeTop])
-		log(node, LogLevelDebug, "left: %g;", node.Layout.Position[EdgeLeft])
-		log(node, LogLevelDebug, "\" ")
+	if format != PrintFormatHTML {
+		// the flex shorthand resets the longhands, so it comes first
+		decls = appendNumberIfNotUndefinedf(decls, "flex", style.Flex)
+	}
+	decls = appendNumberIfNotUndefinedf(decls, "flex-grow", style.FlexGrow)
+	decls = appendNumberIfNotUndefinedf(decls, "flex-shrink", style.FlexShrink)
+	decls = appendNumberIfNotAuto(decls, "flex-basis", &style.FlexBasis)
+	if format == PrintFormatHTML {
+		decls = appendNumberIfNotUndefinedf(decls, "flex", style.Flex)
+	} else {
+		decls = appendNumberIfNotUndefinedf(decls, "aspect-ratio", style.AspectRatio)
 	}
 
-	if options&PrintOptionsStyle != 0 {
-		log(node, LogLevelDebug, "style=\"")
-		if node.Style.FlexDirection != nodeDefaults.Style.FlexDirection {
-			log(node,
-				LogLevelDebug,
-				"flex-direction: %s; ",
-				FlexDirectionToString(node.Style.FlexDirection))
+	if style.FlexWrap != nodeDefaults.Style.FlexWrap {
+		name := "flex-wrap"
+		if format == PrintFormatHTML {
+			name = "flexWrap"
 		}
-		if node.Style.JustifyContent != nodeDefaults.Style.JustifyContent {
-			log(node,
-				LogLevelDebug,
-				"justify-content: %s; ",
-				JustifyToString(node.Style.JustifyContent))
+		decls = append(decls, printDecl{name, WrapToString(style.FlexWrap)})
+	}
+	if style.Overflow != nodeDefaults.Style.Overflow {
+		decls = append(decls, printDecl{"overflow", OverflowToString(style.Overflow)})
+	}
+	if style.Display != nodeDefaults.Style.Display {
+		decls = append(decls, printDecl{"display", DisplayToString(style.Display)})
+	}
+	if style.PointerEvents != nodeDefaults.Style.PointerEvents {
+		decls = append(decls, printDecl{"pointer-events", PointerEventsToString(style.PointerEvents)})
+	}
+	if style.BoxSizing != nodeDefaults.Style.BoxSizing {
+		decls = append(decls, printDecl{"box-sizing", BoxSizingToString(style.BoxSizing)})
+	}
+
+	decls = appendNumberIfNotUndefined(decls, "font-size", &style.FontSize)
+
+	decls = appendEdges(decls, "margin", style.Margin[:], format)
+	decls = appendEdges(decls, "padding", style.Padding[:], format)
+	decls = appendEdges(decls, "border", style.Border[:], format)
+
+	decls = appendNumberIfNotUndefined(decls, "gap", &style.Gap[GutterAll])
+	decls = appendNumberIfNotUndefined(decls, "row-gap", &style.Gap[GutterRow])
+	decls = appendNumberIfNotUndefined(decls, "column-gap", &style.Gap[GutterColumn])
+
+	decls = appendNumberIfNotAuto(decls, "width", &style.Dimensions[DimensionWidth])
+	decls = appendNumberIfNotAuto(decls, "height", &style.Dimensions[DimensionHeight])
+	decls = appendNumberIfNotAuto(decls, "max-width", &style.MaxDimensions[DimensionWidth])
+	decls = appendNumberIfNotAuto(decls, "max-height", &style.MaxDimensions[DimensionHeight])
+	decls = appendNumberIfNotAuto(decls, "min-width", &style.MinDimensions[DimensionWidth])
+	decls = appendNumberIfNotAuto(decls, "min-height", &style.MinDimensions[DimensionHeight])
+
+	if style.PositionType != nodeDefaults.Style.PositionType {
+		decls = append(decls, printDecl{"position", PositionTypeToString(style.PositionType)})
+	}
+
+	decls = appendEdgeIfNotUndefined(decls, "left", style.Position[:], EdgeLeft)
+	decls = appendEdgeIfNotUndefined(decls, "right", style.Position[:], EdgeRight)
+	decls = appendEdgeIfNotUndefined(decls, "top", style.Position[:], EdgeTop)
+	decls = appendEdgeIfNotUndefined(decls, "bottom", style.Position[:], EdgeBottom)
+	return decls
+}
+
+// nodeLayoutValues returns the computed layout of node which is printed
+func nodeLayoutValues(node *Node) []float32 {
+	return []float32{
+		node.Layout.Dimensions[DimensionWidth],
+		node.Layout.Dimensions[DimensionHeight],
+		node.Layout.Position[EdgeTop],
+		node.Layout.Position[EdgeLeft],
+	}
+}
+
+var layoutNames = []string{"width", "height", "top", "left"}
+
+// nodePrinter writes a node tree in one of the print formats
+type nodePrinter struct {
+	w       io.Writer
+	err     error
+	options PrintOptions
+	// callPrintFunc is true if Node.Print should be called for each node
+	callPrintFunc bool
+}
+
+func (p *nodePrinter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *nodePrinter) indent(n int) {
+	p.printf("%s", strings.Repeat("  ", n))
+}
+
+func (p *nodePrinter) printHTML(node *Node, level int) {
+	p.indent(level)
+	p.printf("<div ")
+
+	if p.callPrintFunc && node.Print != nil {
+		node.Print(node)
+	}
+
+	if p.options&PrintOptionsLayout != 0 {
+		p.printf("layout=\"")
+		for i, v := range nodeLayoutValues(node) {
+			if i > 0 {
+				p.printf(" ")
+			}
+			p.printf("%s: %g;", layoutNames[i], v)
 		}
-		if node.Style.AlignItems != nodeDefaults.Style.AlignItems {
-			log(node, LogLevelDebug, "align-items: %s; ", AlignToString(node.Style.AlignItems))
+		p.printf("\" ")
+	}
+
+	if p.options&PrintOptionsStyle != 0 {
+		p.printf("style=\"")
+		for _, decl := range nodeStyleDecls(node, PrintFormatHTML) {
+			p.printf("%s: %s; ", decl.name, decl.value)
 		}
-		if node.Style.AlignContent != nodeDefaults.Style.AlignContent {
-			log(node, LogLevelDebug, "align-content: %s; ", AlignToString(node.Style.AlignContent))
+		p.printf("\" ")
+
+		if node.Measure != nil {
+			p.printf("has-custom-measure=\"true\"")
 		}
-		if node.Style.AlignSelf != nodeDefaults.Style.AlignSelf {
-			log(node, LogLevelDebug, "align-self: %s; ", AlignToString(node.Style.AlignSelf))
+	}
+	p.printf(">")
+
+	childCount := len(node.Children)
+	if p.options&PrintOptionsChildren != 0 && childCount > 0 {
+		for i := 0; i < childCount; i++ {
+			p.printf("\n")
+			p.printHTML(node.Children[i], level+1)
 		}
+		p.indent(level)
+		p.printf("\n")
+	}
+	p.printf("</div>")
+}
+
+// jsonNumber returns f as JSON number, NaN is null
+func jsonNumber(f float32) string {
+	if FloatIsUndefined(f) {
+		return "null"
+	}
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
 
-		printNumberIfNotUndefinedf(node, "flex-grow", node.Style.FlexGrow)
-		printNumberIfNotUndefinedf(node, "flex-shrink", node.Style.FlexShrink)
-		printNumberIfNotAuto(node, "flex-basis", &node.Style.FlexBasis)
-		printNumberIfNotUndefinedf(node, "flex", node.Style.Flex)
+// jsonString returns s as quoted JSON string
+func jsonString(s string) string {
+	d, _ := json.Marshal(s)
+	return string(d)
+}
 
-		if node.Style.FlexWrap != nodeDefaults.Style.FlexWrap {
-			log(node, LogLevelDebug, "flexWrap: %s; ", WrapToString(node.Style.FlexWrap))
+func (p *nodePrinter) printJSON(node *Node, level int) {
+	p.printf("{")
+	sep := "\n"
+	if p.options&PrintOptionsLayout != 0 {
+		p.printf("%s", sep)
+		p.indent(level + 1)
+		p.printf("\"layout\": {")
+		for i, v := range nodeLayoutValues(node) {
+			if i > 0 {
+				p.printf(", ")
+			}
+			p.printf("%s: %s", jsonString(layoutNames[i]), jsonNumber(v))
 		}
+		p.printf("}")
+		sep = ",\n"
+	}
 
-		if node.Style.Overflow != nodeDefaults.Style.Overflow {
-			log(node, LogLevelDebug, "overflow: %s; ", OverflowToString(node.Style.Overflow))
+	if p.options&PrintOptionsStyle != 0 {
+		p.printf("%s", sep)
+		p.indent(level + 1)
+		p.printf("\"style\": {")
+		decls := nodeStyleDecls(node, PrintFormatJSON)
+		for i, decl := range decls {
+			if i > 0 {
+				p.printf(",")
+			}
+			p.printf("\n")
+			p.indent(level + 2)
+			p.printf("%s: %s", jsonString(decl.name), jsonString(decl.value))
+		}
+		if len(decls) > 0 {
+			p.printf("\n")
+			p.indent(level + 1)
 		}
+		p.printf("}")
+		if node.Measure != nil {
+			p.printf(",\n")
+			p.indent(level + 1)
+			p.printf("\"hasCustomMeasure\": true")
+		}
+		sep = ",\n"
+	}
 
-		if node.Style.Display != nodeDefaults.Style.Display {
-			log(node, LogLevelDebug, "display: %s; ", DisplayToString(node.Style.Display))
+	if p.options&PrintOptionsChildren != 0 && len(node.Children) > 0 {
+		p.printf("%s", sep)
+		p.indent(level + 1)
+		p.printf("\"children\": [")
+		for i, child := range node.Children {
+			if i > 0 {
+				p.printf(",")
+			}
+			p.printf("\n")
+			p.indent(level + 2)
+			p.printJSON(child, level+2)
 		}
+		p.printf("\n")
+		p.indent(level + 1)
+		p.printf("]")
+		sep = ",\n"
+	}
 
-		printEdges(node, "margin", node.Style.Margin[:])
-		printEdges(node, "padding", node.Style.Padding[:])
-		printEdges(node, "border", node.Style.Border[:])
+	if sep != "\n" {
+		p.printf("\n")
+		p.indent(level)
+	}
+	p.printf("}")
+}
 
-		printNumberIfNotAuto(node, "width", &node.Style.Dimensions[DimensionWidth])
-		printNumberIfNotAuto(node, "height", &node.Style.Dimensions[DimensionHeight])
-		printNumberIfNotAuto(node, "max-width", &node.Style.MaxDimensions[DimensionWidth])
-		printNumberIfNotAuto(node, "max-height", &node.Style.MaxDimensions[DimensionHeight])
-		printNumberIfNotAuto(node, "min-width", &node.Style.MinDimensions[DimensionWidth])
-		printNumberIfNotAuto(node, "min-height", &node.Style.MinDimensions[DimensionHeight])
+func (p *nodePrinter) printCSS(node *Node, level int) {
+	p.indent(level)
+	p.printf("div {\n")
 
-		if node.Style.PositionType != nodeDefaults.Style.PositionType {
-			log(node,
-				LogLevelDebug,
-				"position: %s; ",
-				PositionTypeToString(node.Style.PositionType))
+	if p.options&PrintOptionsLayout != 0 {
+		p.indent(level + 1)
+		p.printf("/* layout:")
+		for i, v := range nodeLayoutValues(node) {
+			p.printf(" %s: %g;", layoutNames[i], v)
 		}
+		p.printf(" */\n")
+	}
 
-		printEdgeIfNotUndefined(node, "left", node.Style.Position[:], EdgeLeft)
-		printEdgeIfNotUndefined(node, "right", node.Style.Position[:], EdgeRight)
-		printEdgeIfNotUndefined(node, "top", node.Style.Position[:], EdgeTop)
-		printEdgeIfNotUndefined(node, "bottom", node.Style.Position[:], EdgeBottom)
-		log(node, LogLevelDebug, "\" ")
-
+	if p.options&PrintOptionsStyle != 0 {
+		for _, decl := range nodeStyleDecls(node, PrintFormatCSS) {
+			p.indent(level + 1)
+			p.printf("%s: %s;\n", decl.name, decl.value)
+		}
 		if node.Measure != nil {
-			log(node, LogLevelDebug, "has-custom-measure=\"true\"")
+			p.indent(level + 1)
+			p.printf("/* has-custom-measure */\n")
 		}
 	}
-	log(node, LogLevelDebug, ">")
 
-	childCount := len(node.Children)
-	if options&PrintOptionsChildren != 0 && childCount > 0 {
-		for i := 0; i < childCount; i++ {
-			log(node, LogLevelDebug, "\n")
-			nodePrintInternal(node.Children[i], options, level+1)
+	if p.options&PrintOptionsChildren != 0 {
+		for _, child := range node.Children {
+			p.printCSS(child, level+1)
 		}
-		indent(node, level)
-		log(node, LogLevelDebug, "\n")
 	}
-	log(node, LogLevelDebug, "</div>")
+
+	p.indent(level)
+	p.printf("}\n")
+}
+
+// NodeFprint writes node to w in the given format. options select whether
+// layout, style and children are written.
+func NodeFprint(w io.Writer, node *Node, options PrintOptions, format PrintFormat) error {
+	p := &nodePrinter{w: w, options: options}
+	switch format {
+	case PrintFormatHTML:
+		p.printHTML(node, 0)
+		p.printf("\n")
+	case PrintFormatJSON:
+		p.printJSON(node, 0)
+		p.printf("\n")
+	case PrintFormatCSS:
+		p.printCSS(node, 0)
+	default:
+		return fmt.Errorf("flex: unknown print format %d", format)
+	}
+	return p.err
+}
+
+// logWriter writes to the logger of node
+type logWriter struct {
+	node *Node
+}
+
+func (w logWriter) Write(d []byte) (int, error) {
+	log(w.node, LogLevelDebug, "%s", d)
+	return len(d), nil
 }
 
-// NodePrint prints node
+// NodePrint prints node to node's Config.Logger at LogLevelDebug
 func NodePrint(node *Node, options PrintOptions) {
-	nodePrintInternal(node, options, 0)
+	p := &nodePrinter{w: logWriter{node}, options: options, callPrintFunc: true}
+	p.printHTML(node, 0)
+	log(node, LogLevelDebug, "\n")
 }
@@ -0,0 +1,206 @@
+package flex
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeFprint_html(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	root.StyleSetPadding(EdgeAll, 5)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMarginPercent(EdgeLeft, 10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	var sb strings.Builder
+	err := NodeFprint(&sb, root, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren, PrintFormatHTML)
+	assert.Equal(t, nil, err)
+
+	// like NodePrint, the HTML format only prints the four physical edges
+	exp := `<div layout="width: 100; height: 50; top: 0; left: 0;" style="flex-direction: row; width: 100px; height: 50px; " >
+  <div layout="width: 81; height: 40; top: 5; left: 14;" style="flex-grow: 1; margin-left: 10%; " ></div>
+</div>
+`
+	assert.Equal(t, exp, sb.String())
+}
+
+func TestNodeFprint_json(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	root.StyleSetPadding(EdgeAll, 5)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMarginPercent(EdgeLeft, 10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	var sb strings.Builder
+	err := NodeFprint(&sb, root, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren, PrintFormatJSON)
+	assert.Equal(t, nil, err)
+
+	exp := `{
+  "layout": {"width": 100, "height": 50, "top": 0, "left": 0},
+  "style": {
+    "flex-direction": "row",
+    "padding": "5px",
+    "width": "100px",
+    "height": "50px"
+  },
+  "children": [
+    {
+      "layout": {"width": 81, "height": 40, "top": 5, "left": 14},
+      "style": {
+        "flex-grow": "1",
+        "margin-left": "10%"
+      }
+    }
+  ]
+}
+`
+	assert.Equal(t, exp, sb.String())
+
+	var v map[string]interface{}
+	assert.Equal(t, nil, json.Unmarshal([]byte(sb.String()), &v))
+}
+
+func TestNodeFprint_json_options(t *testing.T) {
+	root := NewNode()
+	var sb strings.Builder
+	NodeFprint(&sb, root, 0, PrintFormatJSON)
+	assert.Equal(t, "{}\n", sb.String())
+
+	sb.Reset()
+	NodeFprint(&sb, root, PrintOptionsStyle, PrintFormatJSON)
+	assert.Equal(t, "{\n  \"style\": {}\n}\n", sb.String())
+}
+
+func TestNodeFprint_css(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	root.StyleSetPadding(EdgeAll, 5)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMarginPercent(EdgeLeft, 10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	var sb strings.Builder
+	err := NodeFprint(&sb, root, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren, PrintFormatCSS)
+	assert.Equal(t, nil, err)
+
+	exp := `div {
+  /* layout: width: 100; height: 50; top: 0; left: 0; */
+  flex-direction: row;
+  padding: 5px;
+  width: 100px;
+  height: 50px;
+  div {
+    /* layout: width: 81; height: 40; top: 5; left: 14; */
+    flex-grow: 1;
+    margin-left: 10%;
+  }
+}
+`
+	assert.Equal(t, exp, sb.String())
+}
+
+func TestNodeFprint_without_children(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	root.StyleSetPadding(EdgeAll, 5)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMarginPercent(EdgeLeft, 10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	var sb strings.Builder
+	NodeFprint(&sb, root, PrintOptionsStyle, PrintFormatCSS)
+	assert.Equal(t, "div {\n  flex-direction: row;\n  padding: 5px;\n  width: 100px;\n  height: 50px;\n}\n", sb.String())
+}
+
+// TestNodeFprint_html_baseline pins the output of NodePrint for styles it
+// printed before the JSON and CSS formats were added
+func TestNodeFprint_html_baseline(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceBetween)
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetAlignContent(AlignStretch)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetOverflow(OverflowHidden)
+	root.StyleSetDirection(DirectionRTL)
+	root.StyleSetAspectRatio(2)
+	root.StyleSetWidth(300)
+	root.StyleSetHeight(200)
+	root.StyleSetMargin(EdgeLeft, 4)
+	root.StyleSetMargin(EdgeTop, 4)
+	root.StyleSetMargin(EdgeRight, 4)
+	root.StyleSetMargin(EdgeBottom, 4)
+	root.StyleSetPadding(EdgeLeft, 5)
+	root.StyleSetPadding(EdgeTop, 10)
+	root.StyleSetBorder(EdgeAll, 2)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetAlignSelf(AlignCenter)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetFlexShrink(0.5)
+	rootChild0.StyleSetFlexBasisPercent(50)
+	rootChild0.StyleSetMargin(EdgeStart, 3)
+	rootChild0.StyleSetMaxWidth(100)
+	rootChild0.StyleSetMinHeight(10)
+	rootChild0.StyleSetMinWidthPercent(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	rootChild1.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1.StyleSetFlex(2)
+	rootChild1.StyleSetPosition(EdgeLeft, 10)
+	rootChild1.StyleSetPositionPercent(EdgeTop, 5)
+	rootChild1.StyleSetPosition(EdgeEnd, 7)
+	rootChild1.StyleSetPadding(EdgeLeft, 1)
+	rootChild1.StyleSetPadding(EdgeAll, 2)
+	rootChild1.StyleSetMarginAuto(EdgeRight)
+	rootChild1.StyleSetWidthPercent(50)
+	rootChild1.StyleSetHeightAuto()
+	rootChild1.StyleSetMaxHeightPercent(80)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNode()
+	rootChild2.StyleSetDisplay(DisplayNone)
+	rootChild2.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{Width: 10, Height: 10}
+	})
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	var sb strings.Builder
+	err := NodeFprint(&sb, root, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren, PrintFormatHTML)
+	assert.Equal(t, nil, err)
+
+	exp := `<div layout="width: 300; height: 200; top: 4; left: 4;" style="flex-direction: row; justify-content: space-between; align-items: flex-start; align-content: stretch; flexWrap: wrap; overflow: hidden; margin: 4px; padding-left: 5px; padding-top: 10px; width: 300px; height: 200px; " >
+  <div layout="width: 100; height: 10; top: 100; left: 195;" style="align-self: center; flex-grow: 1; flex-shrink: 0.5; flex-basis: 50%; max-width: 100px; min-width: 10%; min-height: 10px; " ></div>
+  <div layout="width: 146; height: 4; top: 11; left: 9;" style="flex: 2; margin-right: auto; padding-left: 1px; padding-all: 2px; width: 50%; max-height: 80%; position: absolute; left: 10px; top: 5%; " ></div>
+  <div layout="width: 0; height: 0; top: 0; left: 0;" style="display: none; " has-custom-measure="true"></div>
+</div>
+`
+	assert.Equal(t, exp, sb.String())
+}
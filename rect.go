@@ -0,0 +1,90 @@
+package flex
+
+import "math"
+
+// Rect is a rectangle, like the box of a node. Left and Top are inclusive,
+// Right and Bottom exclusive.
+type Rect struct {
+	Left   float32
+	Top    float32
+	Width  float32
+	Height float32
+}
+
+// Right returns the right edge of r
+func (r Rect) Right() float32 {
+	return r.Left + r.Width
+}
+
+// Bottom returns the bottom edge of r
+func (r Rect) Bottom() float32 {
+	return r.Top + r.Height
+}
+
+// IsEmpty returns true if r has no area
+func (r Rect) IsEmpty() bool {
+	return !(r.Width > 0 && r.Height > 0)
+}
+
+// Contains returns true if the point x, y is inside r
+func (r Rect) Contains(x float32, y float32) bool {
+	return x >= r.Left && y >= r.Top && x < r.Right() && y < r.Bottom()
+}
+
+// Intersects returns true if r and o overlap. Empty rects don't overlap
+// anything.
+func (r Rect) Intersects(o Rect) bool {
+	return !r.IsEmpty() && !o.IsEmpty() &&
+		r.Left < o.Right() && o.Left < r.Right() && r.Top < o.Bottom() && o.Top < r.Bottom()
+}
+
+// Intersection returns the overlap of r and o, which is empty if they
+// don't overlap
+func (r Rect) Intersection(o Rect) Rect {
+	left := fmaxf(r.Left, o.Left)
+	top := fmaxf(r.Top, o.Top)
+	return Rect{
+		Left:   left,
+		Top:    top,
+		Width:  fmaxf(fminf(r.Right(), o.Right())-left, 0),
+		Height: fmaxf(fminf(r.Bottom(), o.Bottom())-top, 0),
+	}
+}
+
+// Union returns the smallest rect containing r and o. Empty rects are
+// ignored.
+func (r Rect) Union(o Rect) Rect {
+	if r.IsEmpty() {
+		return o
+	}
+	if o.IsEmpty() {
+		return r
+	}
+	left := fminf(r.Left, o.Left)
+	top := fminf(r.Top, o.Top)
+	return Rect{
+		Left:   left,
+		Top:    top,
+		Width:  fmaxf(r.Right(), o.Right()) - left,
+		Height: fmaxf(r.Bottom(), o.Bottom()) - top,
+	}
+}
+
+// inset returns r with each edge moved inwards by the size returned by edge,
+// not smaller than empty
+func (r Rect) inset(edge func(Edge) float32) Rect {
+	left, top := edge(EdgeLeft), edge(EdgeTop)
+	return Rect{
+		Left:   r.Left + left,
+		Top:    r.Top + top,
+		Width:  fmaxf(r.Width-left-edge(EdgeRight), 0),
+		Height: fmaxf(r.Height-top-edge(EdgeBottom), 0),
+	}
+}
+
+// distance returns the distance from the point x, y to r, 0 if r contains it
+func (r Rect) distance(x float32, y float32) float32 {
+	dx := fmaxf(fmaxf(r.Left-x, x-r.Right()), 0)
+	dy := fmaxf(fmaxf(r.Top-y, y-r.Bottom()), 0)
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
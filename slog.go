@@ -0,0 +1,73 @@
+package flex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// NewSlogLogger returns a Logger which sends messages to logger.
+// Messages are logged in parts (e.g. by NodePrint) so they are buffered
+// and logged as one record for each line.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	var mu sync.Mutex
+	var buf strings.Builder
+	var bufLevel LogLevel
+	var bufNode *Node
+
+	return func(config *Config, node *Node, level LogLevel, format string, args ...interface{}) int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		s := fmt.Sprintf(format, args...)
+		if buf.Len() == 0 {
+			bufLevel = level
+			bufNode = node
+		} else if slogLevel(level) > slogLevel(bufLevel) {
+			bufLevel = level
+		}
+		buf.WriteString(s)
+
+		for {
+			text := buf.String()
+			i := strings.IndexByte(text, '\n')
+			if i < 0 {
+				break
+			}
+			logSlogRecord(logger, bufLevel, bufNode, text[:i])
+			buf.Reset()
+			buf.WriteString(text[i+1:])
+		}
+		return len(s)
+	}
+}
+
+func logSlogRecord(logger *slog.Logger, level LogLevel, node *Node, msg string) {
+	if strings.TrimSpace(msg) == "" {
+		return
+	}
+	attrs := []slog.Attr{slog.String("flex.level", LogLevelToString(level))}
+	if node != nil {
+		attrs = append(attrs, slog.String("flex.node", fmt.Sprintf("%p", node)))
+	}
+	logger.LogAttrs(context.Background(), slogLevel(level), msg, attrs...)
+}
+
+// slogLevel maps level to slog.Level
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelFatal:
+		return slog.LevelError + 4
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelDebug:
+		return slog.LevelDebug
+	}
+	return slog.LevelDebug - 4
+}
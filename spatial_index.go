@@ -0,0 +1,255 @@
+package flex
+
+import "math"
+
+// defaultCellSize is the grid cell size used by NewSpatialIndex when the
+// given one isn't positive
+const defaultCellSize = 64
+
+// largeNodeCells is the number of grid cells above which a node is kept in
+// a list instead of the cells, so big containers don't fill the grid
+const largeNodeCells = 64
+
+// SpatialIndex is a grid over the absolute border boxes of a laid-out tree,
+// for point, rectangle and nearest-node queries on large trees. Boxes are
+// clipped by ancestors with OverflowHidden or OverflowScroll. Nodes with
+// DisplayNone, DisplayContents or an empty visible box are not indexed.
+type SpatialIndex struct {
+	root     *Node
+	cellSize float32
+	cells    map[gridCell]map[*Node]*boxState
+	large    map[*Node]*boxState
+	entries  map[*Node]*boxState
+	// minCell and maxCell bound the cells that were ever used
+	minCell gridCell
+	maxCell gridCell
+}
+
+// gridCell is the column and row of a grid cell
+type gridCell struct {
+	x, y int
+}
+
+// NewSpatialIndex indexes the layout of root and its descendants. Call it
+// after CalculateLayout. cellSize is the size of grid cells, which should
+// be around the size of typical leaf nodes; defaultCellSize is used if it
+// isn't positive.
+func NewSpatialIndex(root *Node, cellSize float32) *SpatialIndex {
+	if !(cellSize > 0) {
+		cellSize = defaultCellSize
+	}
+	idx := &SpatialIndex{
+		root:     root,
+		cellSize: cellSize,
+		cells:    map[gridCell]map[*Node]*boxState{},
+		large:    map[*Node]*boxState{},
+		entries:  map[*Node]*boxState{},
+		minCell:  gridCell{math.MaxInt, math.MaxInt},
+		maxCell:  gridCell{math.MinInt, math.MinInt},
+	}
+	updateBoxes(idx, root, nil, 0, 0, Rect{}, false, true)
+	return idx
+}
+
+// Update updates the index after another CalculateLayout of the root. Only
+// nodes laid out since the previous update and the subtrees of moved nodes
+// are visited.
+func (idx *SpatialIndex) Update() {
+	updateBoxes(idx, idx.root, nil, 0, 0, Rect{}, false, false)
+}
+
+func (idx *SpatialIndex) boxState(node *Node) *boxState {
+	return idx.entries[node]
+}
+
+func (idx *SpatialIndex) setBoxState(node *Node, state *boxState) {
+	if state == nil {
+		delete(idx.entries, node)
+	} else {
+		idx.entries[node] = state
+	}
+}
+
+func (idx *SpatialIndex) visibleChanged(node *Node, old Rect, visible Rect) {
+	if !old.IsEmpty() {
+		idx.unindex(node, old)
+	}
+	if !visible.IsEmpty() {
+		idx.index(node, visible)
+	}
+}
+
+// cellRange returns the cells covered by r
+func (idx *SpatialIndex) cellRange(r Rect) (gridCell, gridCell) {
+	lo := gridCell{idx.cellIndex(r.Left), idx.cellIndex(r.Top)}
+	// Right and Bottom are exclusive
+	hi := gridCell{idx.cellIndex(r.Right()), idx.cellIndex(r.Bottom())}
+	if float32(hi.x)*idx.cellSize == r.Right() && hi.x > lo.x {
+		hi.x--
+	}
+	if float32(hi.y)*idx.cellSize == r.Bottom() && hi.y > lo.y {
+		hi.y--
+	}
+	return lo, hi
+}
+
+func (idx *SpatialIndex) cellIndex(v float32) int {
+	return int(math.Floor(float64(v / idx.cellSize)))
+}
+
+// clampedCellIndex returns the index of the cell containing v, clamped to
+// lo and hi
+func (idx *SpatialIndex) clampedCellIndex(v float32, lo int, hi int) int {
+	cell := math.Floor(float64(v / idx.cellSize))
+	if cell < float64(lo) {
+		return lo
+	}
+	if cell > float64(hi) {
+		return hi
+	}
+	return int(cell)
+}
+
+func (idx *SpatialIndex) index(node *Node, visible Rect) {
+	entry := idx.entries[node]
+	lo, hi := idx.cellRange(visible)
+	if (hi.x-lo.x+1)*(hi.y-lo.y+1) > largeNodeCells {
+		idx.large[node] = entry
+		return
+	}
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			cell := idx.cells[gridCell{x, y}]
+			if cell == nil {
+				cell = map[*Node]*boxState{}
+				idx.cells[gridCell{x, y}] = cell
+			}
+			cell[node] = entry
+		}
+	}
+	idx.minCell = gridCell{min(idx.minCell.x, lo.x), min(idx.minCell.y, lo.y)}
+	idx.maxCell = gridCell{max(idx.maxCell.x, hi.x), max(idx.maxCell.y, hi.y)}
+}
+
+func (idx *SpatialIndex) unindex(node *Node, visible Rect) {
+	if _, ok := idx.large[node]; ok {
+		delete(idx.large, node)
+		return
+	}
+	lo, hi := idx.cellRange(visible)
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			cell := idx.cells[gridCell{x, y}]
+			delete(cell, node)
+			if len(cell) == 0 {
+				delete(idx.cells, gridCell{x, y})
+			}
+		}
+	}
+}
+
+// At returns the nodes whose visible box contains the point x, y, in no
+// particular order. Use HitTest of the root for the topmost node which
+// receives pointer events.
+func (idx *SpatialIndex) At(x float32, y float32) []*Node {
+	var nodes []*Node
+	for node, entry := range idx.cells[gridCell{idx.cellIndex(x), idx.cellIndex(y)}] {
+		if entry.visible.Contains(x, y) {
+			nodes = append(nodes, node)
+		}
+	}
+	for node, entry := range idx.large {
+		if entry.visible.Contains(x, y) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Intersecting returns the nodes whose visible box overlaps r, like the
+// nodes in a viewport, in no particular order
+func (idx *SpatialIndex) Intersecting(r Rect) []*Node {
+	var nodes []*Node
+	seen := map[*Node]bool{}
+	add := func(node *Node, entry *boxState) {
+		if !seen[node] && entry.visible.Intersects(r) {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	if !r.IsEmpty() {
+		lo, hi := idx.cellRange(r)
+		lo = gridCell{max(lo.x, idx.minCell.x), max(lo.y, idx.minCell.y)}
+		hi = gridCell{min(hi.x, idx.maxCell.x), min(hi.y, idx.maxCell.y)}
+		for x := lo.x; x <= hi.x; x++ {
+			for y := lo.y; y <= hi.y; y++ {
+				for node, entry := range idx.cells[gridCell{x, y}] {
+					add(node, entry)
+				}
+			}
+		}
+	}
+	for node, entry := range idx.large {
+		add(node, entry)
+	}
+	return nodes
+}
+
+// Nearest returns the node whose visible box is closest to the point x, y
+// and the distance to it. Of nodes at the same distance, like nodes
+// containing the point, the smallest one is returned. It returns nil if the
+// index is empty.
+func (idx *SpatialIndex) Nearest(x float32, y float32) (*Node, float32) {
+	var best *Node
+	var bestEntry *boxState
+	bestDistance := float32(math.Inf(1))
+	consider := func(node *Node, entry *boxState) {
+		d := entry.visible.distance(x, y)
+		if best == nil || d < bestDistance || (d == bestDistance &&
+			entry.visible.Width*entry.visible.Height < bestEntry.visible.Width*bestEntry.visible.Height) {
+			best, bestEntry, bestDistance = node, entry, d
+		}
+	}
+	for node, entry := range idx.large {
+		consider(node, entry)
+	}
+
+	// search rings of cells around the used cell closest to the point, until
+	// the ring is farther away than the best node or covers all used cells
+	if idx.minCell.x > idx.maxCell.x {
+		return best, bestDistance
+	}
+	center := gridCell{idx.clampedCellIndex(x, idx.minCell.x, idx.maxCell.x),
+		idx.clampedCellIndex(y, idx.minCell.y, idx.maxCell.y)}
+	for ring := 0; ; ring++ {
+		if ring > 0 && float32(ring-1)*idx.cellSize > bestDistance {
+			break
+		}
+		if center.x-ring < idx.minCell.x && center.x+ring > idx.maxCell.x &&
+			center.y-ring < idx.minCell.y && center.y+ring > idx.maxCell.y {
+			break
+		}
+		visit := func(cx int, cy int) {
+			for node, entry := range idx.cells[gridCell{cx, cy}] {
+				consider(node, entry)
+			}
+		}
+		loY, hiY := max(center.y-ring, idx.minCell.y), min(center.y+ring, idx.maxCell.y)
+		for cx := max(center.x-ring, idx.minCell.x); cx <= min(center.x+ring, idx.maxCell.x); cx++ {
+			if cx == center.x-ring || cx == center.x+ring {
+				for cy := loY; cy <= hiY; cy++ {
+					visit(cx, cy)
+				}
+				continue
+			}
+			// inner columns only have cells at the top and bottom of the ring
+			if center.y-ring >= idx.minCell.y {
+				visit(cx, center.y-ring)
+			}
+			if center.y+ring <= idx.maxCell.y {
+				visit(cx, center.y+ring)
+			}
+		}
+	}
+	return best, bestDistance
+}
@@ -0,0 +1,219 @@
+package flex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// visibleBruteForce returns the nodes under root whose visible box is kept
+// by keep
+func visibleBruteForce(node *Node, left, top float32, clip *Rect, keep func(Rect) bool, nodes []*Node) []*Node {
+	if node.Style.Display == DisplayNone {
+		return nodes
+	}
+	box := Rect{left + node.LayoutGetLeft(), top + node.LayoutGetTop(), node.LayoutGetWidth(), node.LayoutGetHeight()}
+	visible := box
+	if clip != nil {
+		visible = box.Intersection(*clip)
+	}
+	if node.Style.Display != DisplayContents && !visible.IsEmpty() && keep(visible) {
+		nodes = append(nodes, node)
+	}
+	if node.Style.Overflow != OverflowVisible {
+		clip = &visible
+	}
+	for _, child := range node.Children {
+		nodes = visibleBruteForce(child, box.Left, box.Top, clip, keep, nodes)
+	}
+	return nodes
+}
+
+func nodeSet(nodes []*Node) map[*Node]bool {
+	set := map[*Node]bool{}
+	for _, node := range nodes {
+		set[node] = true
+	}
+	return set
+}
+
+// checkSpatialIndex compares random queries of idx with queries of the tree
+func checkSpatialIndex(t *testing.T, root *Node, idx *SpatialIndex, rnd *rand.Rand) {
+	for i := 0; i < 200; i++ {
+		x, y := rnd.Float32()*220-10, rnd.Float32()*220-10
+		exp := visibleBruteForce(root, 0, 0, nil, func(box Rect) bool { return box.Contains(x, y) }, nil)
+		got := idx.At(x, y)
+		assert.Equal(t, len(exp), len(got))
+		assert.Equal(t, nodeSet(exp), nodeSet(got))
+
+		r := Rect{x, y, rnd.Float32() * 40, rnd.Float32() * 40}
+		exp = visibleBruteForce(root, 0, 0, nil, r.Intersects, nil)
+		got = idx.Intersecting(r)
+		assert.Equal(t, len(exp), len(got))
+		assert.Equal(t, nodeSet(exp), nodeSet(got))
+	}
+}
+
+func TestSpatialIndex_queries(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(200)
+	for i := 0; i < 400; i++ {
+		cell := NewNodeWithConfig(config)
+		cell.StyleSetWidth(10)
+		cell.StyleSetHeight(10)
+		cell.StyleSetPadding(EdgeAll, 2)
+		inner := NewNodeWithConfig(config)
+		inner.StyleSetHeight(4)
+		cell.InsertChild(inner, 0)
+		root.InsertChild(cell, i)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	idx := NewSpatialIndex(root, 16)
+	checkSpatialIndex(t, root, idx, rand.New(rand.NewSource(1)))
+
+	cell := root.Children[21]
+	inner := cell.Children[0]
+	assert.Equal(t, nodeSet([]*Node{root, cell, inner}), nodeSet(idx.At(13, 13)))
+	assert.Equal(t, nodeSet([]*Node{root, cell}), nodeSet(idx.At(13, 17)))
+	assert.Equal(t, 0, len(idx.At(201, 5)))
+	// a cell size which isn't positive uses the default
+	assert.Equal(t, nodeSet(idx.At(13, 13)), nodeSet(NewSpatialIndex(root, 0).At(13, 13)))
+
+	got := idx.Intersecting(Rect{10, 10, 10, 10})
+	assert.Equal(t, 3, len(got))
+
+	node, distance := idx.Nearest(13, 13)
+	assert.True(t, node == inner)
+	assertFloatEqual(t, 0, distance)
+	node, distance = idx.Nearest(230, 5)
+	assert.True(t, node == root.Children[19])
+	assertFloatEqual(t, 30, distance)
+}
+
+func TestSpatialIndex_incremental_update(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(200)
+	for i := 0; i < 400; i++ {
+		cell := NewNodeWithConfig(config)
+		cell.StyleSetWidth(10)
+		cell.StyleSetHeight(10)
+		cell.StyleSetPadding(EdgeAll, 2)
+		inner := NewNodeWithConfig(config)
+		inner.StyleSetHeight(4)
+		cell.InsertChild(inner, 0)
+		root.InsertChild(cell, i)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	idx := NewSpatialIndex(root, 16)
+	rnd := rand.New(rand.NewSource(2))
+
+	// growing a cell moves the cells after it in the same line and the
+	// following lines
+	unchanged := root.Children[10].Children[0]
+	version := unchanged.layoutVersion
+	root.Children[45].StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, version, unchanged.layoutVersion)
+	idx.Update()
+	checkSpatialIndex(t, root, idx, rnd)
+	grown := root.Children[45]
+	assert.Equal(t, nodeSet([]*Node{root, grown, grown.Children[0]}), nodeSet(idx.At(75, 23)))
+
+	removed := root.Children[0]
+	root.RemoveChild(removed)
+	root.Children[5].StyleSetDisplay(DisplayNone)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	idx.Update()
+	checkSpatialIndex(t, root, idx, rnd)
+	for _, node := range idx.Intersecting(Rect{0, 0, 200, 200}) {
+		assert.True(t, node != removed && node != removed.Children[0])
+	}
+
+	// clipping by a resized container changes the children which didn't move
+	clipper := root.Children[30]
+	clipper.StyleSetOverflow(OverflowHidden)
+	clipper.Children[0].StyleSetWidth(20)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	idx.Update()
+	checkSpatialIndex(t, root, idx, rnd)
+	clipper.StyleSetWidth(5)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	idx.Update()
+	checkSpatialIndex(t, root, idx, rnd)
+}
+
+func TestSpatialIndex_nearest(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(50)
+	for i := 0; i < 25; i++ {
+		cell := NewNodeWithConfig(config)
+		cell.StyleSetWidth(10)
+		cell.StyleSetHeight(10)
+		cell.StyleSetPadding(EdgeAll, 2)
+		inner := NewNodeWithConfig(config)
+		inner.StyleSetHeight(4)
+		cell.InsertChild(inner, 0)
+		root.InsertChild(cell, i)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	idx := NewSpatialIndex(root, 8)
+	rnd := rand.New(rand.NewSource(3))
+	all := visibleBruteForce(root, 0, 0, nil, func(Rect) bool { return true }, nil)
+	for i := 0; i < 100; i++ {
+		x, y := rnd.Float32()*200-50, rnd.Float32()*200-50
+		_, distance := idx.Nearest(x, y)
+		exp := float32(1e9)
+		for _, node := range all {
+			left, top := float32(0), float32(0)
+			for n := node.Parent; n != nil; n = n.Parent {
+				left += n.LayoutGetLeft()
+				top += n.LayoutGetTop()
+			}
+			box := Rect{left + node.LayoutGetLeft(), top + node.LayoutGetTop(), node.LayoutGetWidth(), node.LayoutGetHeight()}
+			exp = fminf(exp, box.distance(x, y))
+		}
+		assertFloatEqual(t, exp, distance)
+	}
+
+	empty := NewSpatialIndex(NewNode(), 8)
+	node, _ := empty.Nearest(0, 0)
+	assert.True(t, node == nil)
+}
+
+func TestSpatialIndex_nearest_far_away(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	for i := 0; i < 100; i++ {
+		cell := NewNodeWithConfig(config)
+		cell.StyleSetWidth(10)
+		cell.StyleSetHeight(10)
+		root.InsertChild(cell, i)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	idx := NewSpatialIndex(root, 10)
+
+	node, distance := idx.Nearest(1e5, 1e5)
+	assert.True(t, node == root.Children[99])
+	assertFloatEqual(t, float32(math.Sqrt(2)*(1e5-100)), distance)
+	node, distance = idx.Nearest(-1e18, 5)
+	assert.True(t, node == root.Children[0])
+	assertFloatEqual(t, 1e18, distance)
+}
@@ -0,0 +1,541 @@
+package flex
+
+import (
+	"sort"
+	"strings"
+)
+
+// Stylesheet is a list of CSS rules applied to nodes by Restyle. Set it as
+// Config.Stylesheet to style the nodes using the config.
+type Stylesheet struct {
+	rules []*styleRule
+}
+
+// styleRule is a selector with its declarations
+type styleRule struct {
+	selector *selector
+	decls    []func(node *Node)
+}
+
+// compoundSelector is a selector without combinators, like "div.a:first-child"
+type compoundSelector struct {
+	// tag is "" for "*" or if there is no type selector
+	tag        string
+	id         string
+	classes    []string
+	firstChild bool
+	lastChild  bool
+	// combinator is ' ' for descendant or '>' for child of the previous
+	// compound selector
+	combinator byte
+}
+
+// selector is a complex selector, like "#menu > .item span"
+type selector struct {
+	parts       []compoundSelector
+	specificity int
+}
+
+func (c *compoundSelector) matches(node *Node) bool {
+	if c.tag != "" && c.tag != node.tag {
+		return false
+	}
+	if c.id != "" && c.id != node.id {
+		return false
+	}
+	for _, class := range c.classes {
+		if !node.HasClass(class) {
+			return false
+		}
+	}
+	parent := node.Parent
+	if c.firstChild && (parent == nil || parent.Children[0] != node) {
+		return false
+	}
+	if c.lastChild && (parent == nil || parent.Children[len(parent.Children)-1] != node) {
+		return false
+	}
+	return true
+}
+
+// matchesPart returns true if node matches parts[:i+1]
+func (s *selector) matchesPart(node *Node, i int) bool {
+	part := &s.parts[i]
+	if !part.matches(node) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	if part.combinator == '>' {
+		return node.Parent != nil && s.matchesPart(node.Parent, i-1)
+	}
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if s.matchesPart(ancestor, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *selector) matches(node *Node) bool {
+	return s.matchesPart(node, len(s.parts)-1)
+}
+
+// compoundSelector parses a compound selector
+func (p *cssParser) compoundSelector() (compoundSelector, error) {
+	var c compoundSelector
+	start := p.pos
+	if p.peek() == '*' {
+		p.pos++
+	} else {
+		c.tag = strings.ToLower(p.ident())
+	}
+	for !p.eof() {
+		switch p.peek() {
+		case '#', '.':
+			kind := p.peek()
+			p.pos++
+			name := p.ident()
+			if name == "" {
+				return c, p.unexpected("expected name")
+			}
+			if kind == '#' {
+				c.id = name
+			} else {
+				c.classes = append(c.classes, name)
+			}
+			continue
+		case ':':
+			pseudoStart := p.pos
+			p.pos++
+			switch p.ident() {
+			case "first-child":
+				c.firstChild = true
+			case "last-child":
+				c.lastChild = true
+			default:
+				p.pos = pseudoStart
+				return c, p.unexpected("unsupported pseudo-class")
+			}
+			continue
+		}
+		break
+	}
+	if p.pos == start {
+		return c, p.unexpected("expected selector")
+	}
+	return c, nil
+}
+
+// selector parses a complex selector ending at ',' or '{'
+func (p *cssParser) selector() (*selector, error) {
+	s := &selector{}
+	combinator := byte(' ')
+	for {
+		p.skipSpace()
+		c, err := p.compoundSelector()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		s.parts = append(s.parts, c)
+
+		hadSpace := !p.eof() && isCSSSpace(p.peek())
+		p.skipSpace()
+		switch p.peek() {
+		case '>':
+			p.pos++
+			combinator = '>'
+		case ',', '{', 0:
+			s.specificity = selectorSpecificity(s)
+			return s, nil
+		default:
+			if !hadSpace {
+				return nil, p.unexpected("unexpected character in selector")
+			}
+			combinator = ' '
+		}
+	}
+}
+
+// selectorSpecificity returns the specificity of s as a single number
+func selectorSpecificity(s *selector) int {
+	ids, classes, tags := 0, 0, 0
+	for _, c := range s.parts {
+		if c.id != "" {
+			ids++
+		}
+		classes += len(c.classes)
+		if c.firstChild {
+			classes++
+		}
+		if c.lastChild {
+			classes++
+		}
+		if c.tag != "" {
+			tags++
+		}
+	}
+	return ids<<20 | classes<<10 | tags
+}
+
+// ParseStylesheet parses CSS rules, like "#menu > .item { margin: 4px }".
+// Selectors can use type, class and id selectors, descendant and child
+// combinators, and :first-child and :last-child. Invalid rules and
+// declarations are skipped and returned as SyntaxErrors, together with a
+// stylesheet with the valid rules.
+func ParseStylesheet(css string) (*Stylesheet, error) {
+	sheet := &Stylesheet{}
+	errs := sheet.parse(css)
+	if len(errs) > 0 {
+		return sheet, toSyntaxErrors(css, errs)
+	}
+	return sheet, nil
+}
+
+func (sheet *Stylesheet) parse(css string) []error {
+	var errs []error
+	p := &cssParser{src: css, end: len(css)}
+	for p.more() {
+		ruleStart := p.pos
+		selectors, selectorErr := p.selectorList()
+
+		open := strings.IndexByte(css[ruleStart:], '{')
+		if open < 0 {
+			return append(errs, selectorErr)
+		}
+		open += ruleStart
+		end := strings.IndexByte(css[open:], '}')
+		if end < 0 {
+			return append(errs, p.errorf(open, "missing '}'"))
+		}
+		end += open
+		p.pos = end + 1
+
+		if selectorErr != nil {
+			errs = append(errs, selectorErr)
+			continue
+		}
+		decls, declErrs := parseDeclarationList(nil, css, open+1, end)
+		errs = append(errs, declErrs...)
+		for _, s := range selectors {
+			sheet.rules = append(sheet.rules, &styleRule{selector: s, decls: decls})
+		}
+	}
+	return errs
+}
+
+// selectorList parses selectors separated by ',' up to '{'
+func (p *cssParser) selectorList() ([]*selector, error) {
+	var selectors []*selector
+	for {
+		s, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '{':
+			return selectors, nil
+		default:
+			return nil, p.unexpected("expected '{'")
+		}
+	}
+}
+
+// matchingRules returns the rules matching node, by specificity and order
+func (sheet *Stylesheet) matchingRules(node *Node) []*styleRule {
+	var rules []*styleRule
+	for _, rule := range sheet.rules {
+		if rule.selector.matches(node) {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].selector.specificity < rules[j].selector.specificity
+	})
+	return rules
+}
+
+// nodeStylesheet returns the stylesheet used by node or nil
+func nodeStylesheet(node *Node) *Stylesheet {
+	if node.Config == nil {
+		return nil
+	}
+	return node.Config.Stylesheet
+}
+
+// Restyle applies Config.Stylesheet to node and its descendants. The style a
+// node has when it is first styled is its base style, and rules are applied
+// on top of it. Changes made with StyleSet* functions between restyles are
+// kept in the base style. Nodes whose style changes are marked dirty.
+// Inserted and removed nodes are restyled by the next Restyle or
+// CalculateLayout.
+func (node *Node) Restyle() {
+	sheet := nodeStylesheet(node)
+	if sheet == nil {
+		return
+	}
+	nodeRestyle(node, sheet)
+}
+
+func nodeRestyle(node *Node, sheet *Stylesheet) {
+	if node.baseStyle == nil {
+		base := node.Style
+		node.baseStyle = &base
+	} else if node.appliedStyle != nil {
+		mergeStyleChanges(node.baseStyle, node.appliedStyle, &node.Style)
+	}
+
+	scratch := Node{Style: *node.baseStyle, Config: node.Config}
+	for _, rule := range sheet.matchingRules(node) {
+		for _, apply := range rule.decls {
+			apply(&scratch)
+		}
+	}
+	// custom properties only make the nodes using them dirty
+	properties := scratch.Style.CustomProperties
+	changedProperties := changedCustomProperties(&scratch.Style, &node.Style)
+	scratch.Style.CustomProperties = node.Style.CustomProperties
+	if !styleEq(&scratch.Style, &node.Style) {
+		fontSizeChanged := !valueEq(scratch.Style.FontSize, node.Style.FontSize)
+		node.Style = scratch.Style
+		if fontSizeChanged {
+			nodeClearUnitBases(node)
+			nodeMarkDirtyRecursive(node)
+		} else {
+			nodeMarkDirtyInternal(node)
+		}
+	}
+	node.Style.CustomProperties = properties
+	if len(changedProperties) > 0 {
+		nodeClearUnitBases(node)
+		nodeMarkCustomPropertyUsersDirty(node, changedProperties)
+	}
+	applied := node.Style
+	node.appliedStyle = &applied
+	node.styleDirty = false
+	node.childStyleDirty = false
+
+	for i := range node.Children {
+		nodeRestyle(nodeChild(node, i), sheet)
+	}
+}
+
+// mergeStyleChanges copies the fields of style which differ from applied,
+// the style set by the last Restyle, into base
+func mergeStyleChanges(base *Style, applied *Style, style *Style) {
+	if style.Direction != applied.Direction {
+		base.Direction = style.Direction
+	}
+	if style.FlexDirection != applied.FlexDirection {
+		base.FlexDirection = style.FlexDirection
+	}
+	if style.JustifyContent != applied.JustifyContent {
+		base.JustifyContent = style.JustifyContent
+	}
+	if style.AlignContent != applied.AlignContent {
+		base.AlignContent = style.AlignContent
+	}
+	if style.AlignItems != applied.AlignItems {
+		base.AlignItems = style.AlignItems
+	}
+	if style.AlignSelf != applied.AlignSelf {
+		base.AlignSelf = style.AlignSelf
+	}
+	if style.PositionType != applied.PositionType {
+		base.PositionType = style.PositionType
+	}
+	if style.FlexWrap != applied.FlexWrap {
+		base.FlexWrap = style.FlexWrap
+	}
+	if style.Overflow != applied.Overflow {
+		base.Overflow = style.Overflow
+	}
+	if style.Display != applied.Display {
+		base.Display = style.Display
+	}
+	if style.BoxSizing != applied.BoxSizing {
+		base.BoxSizing = style.BoxSizing
+	}
+	if style.PointerEvents != applied.PointerEvents {
+		base.PointerEvents = style.PointerEvents
+	}
+	if !feq(style.Flex, applied.Flex) {
+		base.Flex = style.Flex
+	}
+	if !feq(style.FlexGrow, applied.FlexGrow) {
+		base.FlexGrow = style.FlexGrow
+	}
+	if !feq(style.FlexShrink, applied.FlexShrink) {
+		base.FlexShrink = style.FlexShrink
+	}
+	if !feq(style.AspectRatio, applied.AspectRatio) {
+		base.AspectRatio = style.AspectRatio
+	}
+	mergeValueChanges(&base.FlexBasis, &applied.FlexBasis, &style.FlexBasis)
+	mergeValueChanges(&base.FontSize, &applied.FontSize, &style.FontSize)
+	for i := 0; i < EdgeCount; i++ {
+		mergeValueChanges(&base.Margin[i], &applied.Margin[i], &style.Margin[i])
+		mergeValueChanges(&base.Position[i], &applied.Position[i], &style.Position[i])
+		mergeValueChanges(&base.Padding[i], &applied.Padding[i], &style.Padding[i])
+		mergeValueChanges(&base.Border[i], &applied.Border[i], &style.Border[i])
+	}
+	for i := 0; i < 2; i++ {
+		mergeValueChanges(&base.Dimensions[i], &applied.Dimensions[i], &style.Dimensions[i])
+		mergeValueChanges(&base.MinDimensions[i], &applied.MinDimensions[i], &style.MinDimensions[i])
+		mergeValueChanges(&base.MaxDimensions[i], &applied.MaxDimensions[i], &style.MaxDimensions[i])
+	}
+	for i := 0; i < gutterCount; i++ {
+		mergeValueChanges(&base.Gap[i], &applied.Gap[i], &style.Gap[i])
+	}
+
+	changed := changedCustomProperties(applied, style)
+	if len(changed) == 0 {
+		return
+	}
+	properties := make(map[string]Value, len(base.CustomProperties)+len(changed))
+	for name, v := range base.CustomProperties {
+		properties[name] = v
+	}
+	for name := range changed {
+		if v, ok := style.CustomProperties[name]; ok {
+			properties[name] = v
+		} else {
+			delete(properties, name)
+		}
+	}
+	if len(properties) == 0 {
+		properties = nil
+	}
+	base.CustomProperties = properties
+}
+
+func mergeValueChanges(base *Value, applied *Value, value *Value) {
+	if !valueEq(*value, *applied) {
+		*base = *value
+	}
+}
+
+// nodeMarkStyleDirty marks node to be restyled with its descendants by the
+// next CalculateLayout. Restyling when the tree is laid out, instead of on
+// every change, styles a tree built node by node only once.
+func nodeMarkStyleDirty(node *Node) {
+	if nodeStylesheet(node) == nil {
+		return
+	}
+	node.styleDirty = true
+	for parent := node.Parent; parent != nil && !parent.childStyleDirty; parent = parent.Parent {
+		parent.childStyleDirty = true
+	}
+}
+
+// nodeMarkChildrenStyleDirty marks child, which was inserted into or removed
+// from node, and the two first and two last children of node, which may now
+// match :first-child and :last-child differently. child can be nil.
+func nodeMarkChildrenStyleDirty(node *Node, child *Node) {
+	if nodeStylesheet(node) == nil {
+		return
+	}
+	if child != nil {
+		nodeMarkStyleDirty(child)
+	}
+	n := len(node.Children)
+	for i := range node.Children {
+		if node.Children[i] != child && (i < 2 || i >= n-2) {
+			nodeMarkStyleDirty(nodeChild(node, i))
+		}
+	}
+}
+
+// nodeRestyleDirty restyles the nodes marked by nodeMarkStyleDirty
+func nodeRestyleDirty(node *Node) {
+	if !node.styleDirty && !node.childStyleDirty {
+		return
+	}
+	sheet := nodeStylesheet(node)
+	if sheet == nil {
+		return
+	}
+	if node.styleDirty {
+		nodeRestyle(node, sheet)
+		return
+	}
+	node.childStyleDirty = false
+	for i, child := range node.Children {
+		if child.styleDirty || child.childStyleDirty {
+			nodeRestyleDirty(nodeChild(node, i))
+		}
+	}
+}
+
+// ID returns the id of node matched by "#id" selectors
+func (node *Node) ID() string {
+	return node.id
+}
+
+// SetID sets the id of node and restyles it
+func (node *Node) SetID(id string) {
+	if node.id != id {
+		node.id = id
+		node.Restyle()
+	}
+}
+
+// Tag returns the tag of node matched by type selectors
+func (node *Node) Tag() string {
+	return node.tag
+}
+
+// SetTag sets the tag of node and restyles it. Tags are lower case, like
+// HTML element names.
+func (node *Node) SetTag(tag string) {
+	tag = strings.ToLower(tag)
+	if node.tag != tag {
+		node.tag = tag
+		node.Restyle()
+	}
+}
+
+// Classes returns the classes of node
+func (node *Node) Classes() []string {
+	return node.classes
+}
+
+// SetClasses sets the classes of node and restyles it
+func (node *Node) SetClasses(classes ...string) {
+	node.classes = append([]string(nil), classes...)
+	node.Restyle()
+}
+
+// HasClass returns true if node has class
+func (node *Node) HasClass(class string) bool {
+	for _, c := range node.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// AddClass adds class to node and restyles it
+func (node *Node) AddClass(class string) {
+	if !node.HasClass(class) {
+		node.classes = append(node.classes, class)
+		node.Restyle()
+	}
+}
+
+// RemoveClass removes class from node and restyles it
+func (node *Node) RemoveClass(class string) {
+	for i, c := range node.classes {
+		if c == class {
+			node.classes = append(node.classes[:i:i], node.classes[i+1:]...)
+			node.Restyle()
+			return
+		}
+	}
+}
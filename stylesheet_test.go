@@ -0,0 +1,157 @@
+package flex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStyledTree(t *testing.T, css string, html string) *Node {
+	sheet, err := ParseStylesheet(css)
+	assert.Equal(t, nil, err)
+	config := NewConfig()
+	config.Stylesheet = sheet
+	root, err := ParseNodeHTML(html, config)
+	assert.Equal(t, nil, err)
+	return root
+}
+
+func TestStylesheet_selectors(t *testing.T) {
+	root := newStyledTree(t, `
+		div { width: 10px }
+		.wide { width: 20px }
+		#main { width: 30px }
+		div.item span { height: 5px }
+		#list > span { height: 7px }
+	`, `<div id="list">
+		<div class="item"><span></span></div>
+		<span class="wide"></span>
+		<div id="main" class="wide"></div>
+	</div>`)
+
+	item := root.Children[0]
+	assert.Equal(t, "div", item.Tag())
+	assert.Equal(t, []string{"item"}, item.Classes())
+	assert.Equal(t, Value{Value: 10, Unit: UnitPoint}, item.StyleGetWidth())
+	assert.Equal(t, Value{Value: 5, Unit: UnitPoint}, item.Children[0].StyleGetHeight())
+	assert.Equal(t, Value{Value: 20, Unit: UnitPoint}, root.Children[1].StyleGetWidth())
+	assert.Equal(t, Value{Value: 7, Unit: UnitPoint}, root.Children[1].StyleGetHeight())
+	assert.Equal(t, "main", root.Children[2].ID())
+	assert.Equal(t, Value{Value: 30, Unit: UnitPoint}, root.Children[2].StyleGetWidth())
+}
+
+func TestStylesheet_specificity_and_order(t *testing.T) {
+	root := newStyledTree(t, `
+		.a.b { height: 1px }
+		.a { height: 2px; width: 2px }
+		div { height: 3px; width: 3px }
+		.a { width: 4px }
+	`, `<div class="a b"></div>`)
+	assert.Equal(t, Value{Value: 1, Unit: UnitPoint}, root.StyleGetHeight())
+	assert.Equal(t, Value{Value: 4, Unit: UnitPoint}, root.StyleGetWidth())
+}
+
+func TestStylesheet_first_and_last_child(t *testing.T) {
+	root := newStyledTree(t, `
+		:first-child { margin-left: 1px }
+		span:last-child { margin-right: 2px }
+	`, `<div><span></span><span></span></div>`)
+	first, last := root.Children[0], root.Children[1]
+	assert.Equal(t, Value{Value: 1, Unit: UnitPoint}, first.Style.Margin[EdgeLeft])
+	assert.True(t, valueEq(ValueUndefined, first.Style.Margin[EdgeRight]))
+	assert.Equal(t, Value{Value: 2, Unit: UnitPoint}, last.Style.Margin[EdgeRight])
+	assert.True(t, valueEq(ValueUndefined, root.Style.Margin[EdgeLeft]))
+
+	child := NewNodeWithConfig(root.Config)
+	child.SetTag("SPAN")
+	root.InsertChild(child, 0)
+	// inserted nodes are styled by the next layout
+	assert.True(t, valueEq(ValueUndefined, child.Style.Margin[EdgeLeft]))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, Value{Value: 1, Unit: UnitPoint}, child.Style.Margin[EdgeLeft])
+	assert.True(t, valueEq(ValueUndefined, first.Style.Margin[EdgeLeft]))
+
+	// a removed node is restyled by its own layout
+	root.RemoveChild(last)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	CalculateLayout(last, Undefined, Undefined, DirectionLTR)
+	assert.True(t, valueEq(ValueUndefined, last.Style.Margin[EdgeRight]))
+	assert.Equal(t, Value{Value: 2, Unit: UnitPoint}, first.Style.Margin[EdgeRight])
+}
+
+func TestStylesheet_class_change_dirties_affected_nodes(t *testing.T) {
+	root := newStyledTree(t, `
+		.big { width: 50px }
+		.big > .inner { height: 5px }
+	`, `<div style="width: 200px">
+		<div class="a"><div class="inner"></div></div>
+		<div class="b"></div>
+	</div>`)
+	a, b := root.Children[0], root.Children[1]
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.False(t, root.IsDirty)
+
+	b.AddClass("x")
+	assert.False(t, b.IsDirty)
+	assert.False(t, root.IsDirty)
+
+	a.AddClass("big")
+	assert.True(t, a.IsDirty)
+	assert.True(t, root.IsDirty)
+	assert.True(t, a.Children[0].IsDirty)
+	assert.False(t, b.IsDirty)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 50, a.LayoutGetWidth())
+	assertFloatEqual(t, 5, a.Children[0].LayoutGetHeight())
+
+	a.RemoveClass("big")
+	assert.True(t, a.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 200, a.LayoutGetWidth())
+	assertFloatEqual(t, 0, a.Children[0].LayoutGetHeight())
+}
+
+func TestStylesheet_base_style(t *testing.T) {
+	root := newStyledTree(t, `.a { width: 10px }`, `<div style="height: 3px"></div>`)
+	root.StyleSetWidth(40)
+	root.StyleSetCustomProperty("--gap", Value{Value: 2, Unit: UnitPoint})
+	assert.Equal(t, Value{Value: 40, Unit: UnitPoint}, root.StyleGetWidth())
+
+	root.AddClass("a")
+	assert.Equal(t, Value{Value: 10, Unit: UnitPoint}, root.StyleGetWidth())
+	assert.Equal(t, Value{Value: 3, Unit: UnitPoint}, root.StyleGetHeight())
+	root.SetClasses()
+	assert.Equal(t, Value{Value: 40, Unit: UnitPoint}, root.StyleGetWidth())
+
+	// changes between restyles are kept
+	root.AddClass("b")
+	root.StyleSetWidth(33)
+	root.StyleSetHeightAuto()
+	root.AddClass("c")
+	assert.Equal(t, Value{Value: 33, Unit: UnitPoint}, root.StyleGetWidth())
+	assert.True(t, valueEq(ValueAuto, root.StyleGetHeight()))
+	root.AddClass("a")
+	assert.Equal(t, Value{Value: 10, Unit: UnitPoint}, root.StyleGetWidth())
+	root.RemoveClass("a")
+	assert.Equal(t, Value{Value: 33, Unit: UnitPoint}, root.StyleGetWidth())
+	_, ok := root.StyleGetCustomProperty("--gap")
+	assert.True(t, ok)
+}
+
+func TestParseStylesheet_errors(t *testing.T) {
+	css := "div { width: 1px }\n.a:hover { width: 2px }\nspan { width: nope; height: 3px }"
+	sheet, err := ParseStylesheet(css)
+	var errs SyntaxErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, 2, errs[0].Line)
+	assert.Equal(t, 3, errs[0].Column)
+	assert.Equal(t, 3, errs[1].Line)
+	assert.Equal(t, 2, len(sheet.rules))
+	assert.Equal(t, 1, len(sheet.rules[1].decls))
+
+	_, err = ParseStylesheet("div { width: 1px")
+	assert.True(t, errors.As(err, &errs))
+}
@@ -0,0 +1,166 @@
+package flex
+
+// unitBases holds the sizes viewport and font-relative units are resolved
+// against, and the custom properties used by var().
+type unitBases struct {
+	fontSize       float32
+	rootFontSize   float32
+	viewportWidth  float32
+	viewportHeight float32
+	// rootViewport is the size given to CalculateLayout of the root
+	rootViewport [2]float32
+	// properties are the custom properties set on the node or inherited,
+	// cyclic the ones whose value depends on itself
+	properties map[string]Value
+	cyclic     map[string]bool
+	// generation is the layout pass the bases were computed for
+	generation int
+}
+
+// nodeUnitBases returns the bases of node. They are computed once per
+// layout pass from the bases of the parent, see nodeUpdateChildUnitBases;
+// nodes which weren't reached that way compute them from their ancestors.
+func nodeUnitBases(node *Node) *unitBases {
+	if node.bases != nil {
+		return node.bases
+	}
+	return nodeComputeUnitBases(node)
+}
+
+// nodeComputeUnitBases computes the bases of node from its ancestors
+func nodeComputeUnitBases(node *Node) *unitBases {
+	if node.Parent == nil {
+		return deriveUnitBases(node, nil)
+	}
+	return deriveUnitBases(node, nodeComputeUnitBases(node.Parent))
+}
+
+// nodeUpdateChildUnitBases computes the bases of the children of node, and
+// of its children with display: contents, which are laid out by node,
+// unless they were computed in this layout pass already
+func nodeUpdateChildUnitBases(node *Node, generation int) {
+	bases := nodeUnitBases(node)
+	for _, child := range node.Children {
+		if child.bases == nil || child.bases.generation != generation {
+			child.bases = deriveUnitBases(child, bases)
+			child.bases.generation = generation
+		}
+		if child.Style.Display == DisplayContents {
+			nodeUpdateChildUnitBases(child, generation)
+		}
+	}
+}
+
+// nodeClearUnitBases drops the bases of node and its descendants, after a
+// change of the inherited font size or of the parent of node
+func nodeClearUnitBases(node *Node) {
+	node.bases = nil
+	for _, child := range node.Children {
+		// shared children in copy-on-write mode get their own bases when
+		// they are cloned
+		if child.Parent == node {
+			nodeClearUnitBases(child)
+		}
+	}
+}
+
+// deriveUnitBases returns the bases of node with parent, the bases of its
+// parent or nil for the root. Font size is inherited from the parent, the
+// root inherits Config.FontSize.
+func deriveUnitBases(node *Node, parent *unitBases) *unitBases {
+	bases := &unitBases{}
+	if parent == nil {
+		bases.fontSize = node.Config.FontSize
+		bases.rootFontSize = node.Config.FontSize
+		bases.rootViewport = node.viewport
+	} else {
+		*bases = *parent
+	}
+	bases.properties, bases.cyclic = inheritCustomProperties(bases.properties, bases.cyclic, node.Style.CustomProperties)
+
+	bases.viewportWidth = node.Config.ViewportWidth
+	bases.viewportHeight = node.Config.ViewportHeight
+	if FloatIsUndefined(bases.viewportWidth) {
+		bases.viewportWidth = bases.rootViewport[DimensionWidth]
+	}
+	if FloatIsUndefined(bases.viewportHeight) {
+		bases.viewportHeight = bases.rootViewport[DimensionHeight]
+	}
+
+	// em and percent in font size are relative to the parent font size
+	value := &node.Style.FontSize
+	if value.Unit != UnitUndefined && value.Unit != UnitAuto {
+		bases.fontSize = resolveValueWithBases(value, bases.fontSize, bases)
+	}
+	if parent == nil {
+		bases.rootFontSize = bases.fontSize
+	}
+	return bases
+}
+
+// nodeMarkViewportDependentDirty marks nodes using viewport units dirty. If
+// the font size uses them, the whole subtree is marked, as em units of
+// descendants depend on it.
+func nodeMarkViewportDependentDirty(node *Node) {
+	if valueUsesViewport(&node.Style.FontSize) {
+		nodeMarkDirtyRecursive(node)
+		return
+	}
+	if styleUsesViewport(&node.Style) {
+		nodeMarkDirtyInternal(node)
+	}
+	for i := range node.Children {
+		nodeMarkViewportDependentDirty(nodeChild(node, i))
+	}
+}
+
+// styleValues returns the values of style that can use any unit. Borders
+// are always points.
+func styleValues(style *Style) []*Value {
+	values := []*Value{&style.FlexBasis, &style.FontSize}
+	for i := range style.Dimensions {
+		values = append(values, &style.Dimensions[i], &style.MinDimensions[i], &style.MaxDimensions[i])
+	}
+	for i := range style.Gap {
+		values = append(values, &style.Gap[i])
+	}
+	for i := 0; i < EdgeCount; i++ {
+		values = append(values, &style.Margin[i], &style.Padding[i], &style.Position[i])
+	}
+	return values
+}
+
+func styleUsesViewport(style *Style) bool {
+	for _, value := range styleValues(style) {
+		if valueUsesViewport(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueUsesViewport(value *Value) bool {
+	switch value.Unit {
+	case UnitVw, UnitVh, UnitVmin, UnitVmax:
+		return true
+	case UnitCalc:
+		return value.Calc.usesViewport()
+	}
+	return false
+}
+
+// nodeMarkDirtyRecursive marks node and all its descendants dirty, for style
+// changes that are inherited.
+func nodeMarkDirtyRecursive(node *Node) {
+	nodeMarkDirtyInternal(node)
+	nodeMarkDescendantsDirty(node)
+}
+
+func nodeMarkDescendantsDirty(node *Node) {
+	for i := range node.Children {
+		child := nodeChild(node, i)
+		child.IsDirty = true
+		child.Layout.computedFlexBasis = Undefined
+		nodeMarkDescendantsDirty(child)
+	}
+}
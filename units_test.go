@@ -0,0 +1,195 @@
+package flex
+
+import "testing"
+
+func TestUnits_viewport_from_calculate_layout(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidthValue(Value{Value: 100, Unit: UnitVw})
+	root.StyleSetHeightValue(Value{Value: 50, Unit: UnitVh})
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetMarginValue(EdgeLeft, Value{Value: 10, Unit: UnitVmin})
+	rootChild0.StyleSetPaddingValue(EdgeTop, Value{Value: 1, Unit: UnitVmax})
+	rootChild0.StyleSetWidthValue(Value{Value: 25, Unit: UnitVw})
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, 800, 600, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 800, root.LayoutGetWidth())
+	assertFloatEqual(t, 300, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 200, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 8, rootChild0.LayoutGetPadding(EdgeTop))
+}
+
+func TestUnits_viewport_from_config(t *testing.T) {
+	config := NewConfig()
+	config.ViewportWidth = 1000
+	config.ViewportHeight = 500
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidthValue(Value{Value: 50, Unit: UnitVw})
+	root.StyleSetHeightValue(Value{Value: 50, Unit: UnitVh})
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 500, root.LayoutGetWidth())
+	assertFloatEqual(t, 250, root.LayoutGetHeight())
+}
+
+func TestUnits_viewport_change_relayouts_cached_subtree(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(50)
+	rootChild0.StyleSetHeight(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetMarginValue(EdgeLeft, Value{Value: 1, Unit: UnitVw})
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, 1000, 1000, DirectionLTR)
+
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetWidth())
+
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 45, rootChild0Child0.LayoutGetWidth())
+}
+
+func TestUnits_em_and_rem(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFontSize(20)
+	root.StyleSetWidth(500)
+	root.StyleSetHeight(500)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthValue(Value{Value: 5, Unit: UnitEm})
+	rootChild0.StyleSetHeight(100)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetFontSizeValue(Value{Value: 2, Unit: UnitEm})
+	rootChild0Child0.StyleSetWidthValue(Value{Value: 1, Unit: UnitEm})
+	rootChild0Child0.StyleSetHeightValue(Value{Value: 1, Unit: UnitRem})
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child1 := NewNodeWithConfig(config)
+	rootChild0Child1.StyleSetFontSizeValue(Value{Value: 150, Unit: UnitPercent})
+	rootChild0Child1.StyleSetWidthValue(Value{Value: 2, Unit: UnitEm})
+	rootChild0Child1.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 100, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetHeight())
+	assertFloatEqual(t, 60, rootChild0Child1.LayoutGetWidth())
+
+	root.StyleSetFontSize(10)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetHeight())
+	assertFloatEqual(t, 30, rootChild0Child1.LayoutGetWidth())
+}
+
+func TestUnits_em_uses_config_font_size(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidthValue(Value{Value: 2, Unit: UnitEm})
+	root.StyleSetHeightValue(Value{Value: 3, Unit: UnitRem})
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 32, root.LayoutGetWidth())
+	assertFloatEqual(t, 48, root.LayoutGetHeight())
+}
+
+func TestUnits_in_calc(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFontSize(10)
+	root.StyleSetWidthCalc(CalcSub(CalcValue(Value{Value: 50, Unit: UnitVw}), CalcValue(Value{Value: 2, Unit: UnitEm})))
+	root.StyleSetHeight(10)
+	CalculateLayout(root, 400, 400, DirectionLTR)
+
+	assertFloatEqual(t, 180, root.LayoutGetWidth())
+
+	CalculateLayout(root, 200, 400, DirectionLTR)
+
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+}
+
+func TestUnits_min_content_contribution(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFontSize(10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthMinContent()
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetWidthValue(Value{Value: 5, Unit: UnitEm})
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child1 := NewNodeWithConfig(config)
+	rootChild0Child1.StyleSetWidthValue(Value{Value: 20, Unit: UnitVw})
+	rootChild0Child1.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child1, 1)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 100, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0Child1.LayoutGetWidth())
+}
+
+func TestUnits_font_size_change_reaches_intrinsic_descendants(t *testing.T) {
+	config := NewConfig()
+	root := NewNodeWithConfig(config)
+	root.StyleSetFontSize(10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidthMinContent()
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0Child0.StyleSetWidthValue(Value{Value: 2, Unit: UnitEm})
+	rootChild0Child0Child0.StyleSetHeight(10)
+	rootChild0Child0.InsertChild(rootChild0Child0Child0, 0)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+
+	root.StyleSetFontSize(20)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 40, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 40, rootChild0Child0Child0.LayoutGetWidth())
+
+	// a node moved to another parent inherits the font size of the new one
+	rootChild0Child0.RemoveChild(rootChild0Child0Child0)
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFontSize(5)
+	rootChild1.StyleSetWidthMinContent()
+	rootChild1.InsertChild(rootChild0Child0Child0, 0)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, 500, 500, DirectionLTR)
+
+	assertFloatEqual(t, 10, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0Child0.LayoutGetWidth())
+}
@@ -3,6 +3,7 @@ package flex
 import (
 	"fmt"
 	"os"
+	"sync/atomic"
 )
 
 // CachedMeasurement describes measurements
@@ -69,6 +70,17 @@ type Style struct {
 	Dimensions     [2]Value
 	MinDimensions  [2]Value
 	MaxDimensions  [2]Value
+	Gap            [gutterCount]Value
+	BoxSizing      BoxSizing
+	FontSize       Value
+	// PointerEvents is only used by HitTest and doesn't change the layout
+	PointerEvents PointerEvents
+
+	// CustomProperties are CSS custom properties, like "--spacing", which
+	// values of the node and its descendants can use with ValueVar. The map
+	// is shared between copies of the style and must not be modified, use
+	// StyleSetCustomProperty.
+	CustomProperties map[string]Value
 
 	// Yoga specific properties, not compatible with flexbox specification
 	AspectRatio float32
@@ -82,6 +94,43 @@ type Config struct {
 	PointScaleFactor          float32
 	Logger                    Logger
 	Context                   interface{}
+
+	// LogLevel is the most verbose level passed to Logger. Fatal messages
+	// are always logged.
+	LogLevel LogLevel
+
+	// BoxSizing is the box sizing of nodes created with this config
+	BoxSizing BoxSizing
+
+	// FontSize is the font size of the root, used by em and rem units
+	FontSize float32
+	// ViewportWidth and ViewportHeight are used by viewport units. If
+	// undefined, the size passed to CalculateLayout is used.
+	ViewportWidth  float32
+	ViewportHeight float32
+
+	// Stylesheet is applied to nodes with this config by Restyle
+	Stylesheet *Stylesheet
+
+	// CopyOnWrite allows subtrees to be shared by several trees. A child
+	// whose Parent is not the node listing it is shared, and layout and
+	// recursive style changes replace it by a clone instead of changing it.
+	CopyOnWrite bool
+	// CloneNodeFunc clones shared nodes in copy-on-write mode. If nil,
+	// Clone with CloneOptionsKeepLayout is used. Like Clone, it must give
+	// the clone its own Children slice.
+	CloneNodeFunc CloneNodeFunc
+
+	// ParallelWorkers is the maximum number of goroutines used by a single
+	// CalculateLayout call. Children whose size is fully determined by their
+	// parent are laid out in parallel. Values below 2 disable parallel layout.
+	// When enabled, Measure and Baseline functions must be safe for concurrent use.
+	ParallelWorkers int
+
+	// Debug output of layouts calculated with this config
+	PrintTree    bool
+	PrintChanges bool
+	PrintSkips   bool
 }
 
 // Node describes a an element
@@ -105,7 +154,38 @@ type Node struct {
 	hasNewLayout bool
 	NodeType     NodeType
 
-	resolvedDimensions [2]*Value
+	// layoutVersion is incremented with each new layout, like hasNewLayout
+	// is set, so ChangedFrames, SpatialIndex and DamageTracker can each tell
+	// the nodes laid out since they last looked
+	layoutVersion uint64
+	// frame is the frame last returned by ChangedFrames, at frameVersion of
+	// the layout
+	frame        Rect
+	frameVersion uint64
+
+	resolvedDimensions  [2]*Value
+	intrinsicDimensions [2]Value
+
+	// viewport is the size passed to CalculateLayout
+	viewport [2]float32
+	// bases are the font sizes, viewport and custom properties values of
+	// the node are resolved with, set by the layout of its parent
+	bases *unitBases
+
+	// id, tag and classes are matched by Stylesheet selectors
+	id      string
+	tag     string
+	classes []string
+	// baseStyle is the style without stylesheet rules, nil if the node
+	// has not been styled
+	baseStyle *Style
+	// appliedStyle is the style set by the last Restyle, which tells the
+	// StyleSet* changes made since then
+	appliedStyle *Style
+	// styleDirty is set if node and its descendants must be restyled,
+	// childStyleDirty if some of its descendants must be
+	styleDirty      bool
+	childStyleDirty bool
 }
 
 var (
@@ -131,6 +211,12 @@ var (
 		undefinedValue,
 	}
 
+	defaultGutterValuesUnit = [gutterCount]Value{
+		undefinedValue,
+		undefinedValue,
+		undefinedValue,
+	}
+
 	defaultDimensionValues = [2]float32{
 		Undefined,
 		Undefined,
@@ -161,6 +247,7 @@ var (
 		IsDirty:            false,
 		NodeType:           NodeTypeDefault,
 		resolvedDimensions: [2]*Value{&ValueUndefined, &ValueUndefined},
+		viewport:           [2]float32{Undefined, Undefined},
 		Style: Style{
 			Flex:           Undefined,
 			FlexGrow:       Undefined,
@@ -180,6 +267,8 @@ var (
 			Margin:         defaultEdgeValuesUnit,
 			Padding:        defaultEdgeValuesUnit,
 			Border:         defaultEdgeValuesUnit,
+			Gap:            defaultGutterValuesUnit,
+			FontSize:       undefinedValue,
 			AspectRatio:    Undefined,
 		},
 		Layout: Layout{
@@ -205,7 +294,11 @@ var (
 		},
 		UseWebDefaults:   false,
 		PointScaleFactor: 1,
+		FontSize:         16,
+		ViewportWidth:    Undefined,
+		ViewportHeight:   Undefined,
 		Logger:           DefaultLog,
+		LogLevel:         LogLevelVerbose,
 		Context:          nil,
 	}
 
@@ -217,6 +310,9 @@ func valueEq(v1, v2 Value) bool {
 	if v1.Unit != v2.Unit {
 		return false
 	}
+	if v1.Unit == UnitCalc {
+		return calcEqual(v1.Calc, v2.Calc)
+	}
 	return feq(v1.Value, v2.Value)
 }
 
@@ -261,7 +357,9 @@ func computedEdgeValue(edges []Value, edge Edge, defaultValue *Value) *Value {
 	return defaultValue
 }
 
-func resolveValue(value *Value, parentSize float32) float32 {
+// nodeResolveValue resolves a style value of node. Viewport and font-relative
+// units need node to find the viewport and font sizes.
+func nodeResolveValue(node *Node, value *Value, parentSize float32) float32 {
 	switch value.Unit {
 	case UnitUndefined, UnitAuto:
 		return Undefined
@@ -270,14 +368,38 @@ func resolveValue(value *Value, parentSize float32) float32 {
 	case UnitPercent:
 		return value.Value * parentSize / 100
 	}
+	return resolveValueWithBases(value, parentSize, nodeUnitBases(node))
+}
+
+func resolveValueWithBases(value *Value, parentSize float32, bases *unitBases) float32 {
+	switch value.Unit {
+	case UnitPoint:
+		return value.Value
+	case UnitPercent:
+		return value.Value * parentSize / 100
+	case UnitCalc:
+		return value.Calc.resolve(parentSize, bases)
+	case UnitVw:
+		return value.Value * bases.viewportWidth / 100
+	case UnitVh:
+		return value.Value * bases.viewportHeight / 100
+	case UnitVmin:
+		return value.Value * fminf(bases.viewportWidth, bases.viewportHeight) / 100
+	case UnitVmax:
+		return value.Value * fmaxf(bases.viewportWidth, bases.viewportHeight) / 100
+	case UnitEm:
+		return value.Value * bases.fontSize
+	case UnitRem:
+		return value.Value * bases.rootFontSize
+	}
 	return Undefined
 }
 
-func resolveValueMargin(value *Value, parentSize float32) float32 {
+func nodeResolveValueMargin(node *Node, value *Value, parentSize float32) float32 {
 	if value.Unit == UnitAuto {
 		return 0
 	}
-	return resolveValue(value, parentSize)
+	return nodeResolveValue(node, value, parentSize)
 }
 
 // NewNodeWithConfig creates new node with config
@@ -288,6 +410,7 @@ func NewNodeWithConfig(config *Config) *Node {
 		node.Style.FlexDirection = FlexDirectionRow
 		node.Style.AlignContent = AlignStretch
 	}
+	node.Style.BoxSizing = config.BoxSizing
 	node.Config = config
 	return &node
 }
@@ -299,8 +422,8 @@ func NewNode() *Node {
 
 // Reset resets a node
 func (node *Node) Reset() {
-	assertWithNode(node, len(node.Children) == 0, "Cannot reset a node which still has children attached")
-	assertWithNode(node, node.Parent == nil, "Cannot reset a node still attached to a parent")
+	assertWithNode(node, len(node.Children) == 0, ErrResetWithChildren)
+	assertWithNode(node, node.Parent == nil, ErrResetWithParent)
 
 	node.Children = nil
 
@@ -310,6 +433,7 @@ func (node *Node) Reset() {
 		node.Style.FlexDirection = FlexDirectionRow
 		node.Style.AlignContent = AlignStretch
 	}
+	node.Style.BoxSizing = config.BoxSizing
 	node.Config = config
 }
 
@@ -349,10 +473,7 @@ func (node *Node) SetMeasureFunc(measureFunc MeasureFunc) {
 		// TODO: t18095186 Move nodeType to opt-in function and mark appropriate places in Litho
 		node.NodeType = NodeTypeDefault
 	} else {
-		assertWithNode(
-			node,
-			len(node.Children) == 0,
-			"Cannot set measure function: Nodes with measure functions cannot have children.")
+		assertWithNode(node, len(node.Children) == 0, ErrMeasureWithChildren)
 		node.Measure = measureFunc
 		// TODO: t18095186 Move nodeType to opt-in function and mark appropriate places in Litho
 		node.NodeType = NodeTypeText
@@ -361,9 +482,12 @@ func (node *Node) SetMeasureFunc(measureFunc MeasureFunc) {
 
 // InsertChild inserts a child
 func (node *Node) InsertChild(child *Node, idx int) {
-	assertWithNode(node, child.Parent == nil, "Child already has a parent, it must be removed first.")
-	assertWithNode(node, node.Measure == nil, "Cannot add child: Nodes with measure functions cannot have children.")
+	if err := node.InsertChildE(child, idx); err != nil {
+		panic(err)
+	}
+}
 
+func (node *Node) insertChild(child *Node, idx int) {
 	a := node.Children
 	// https://github.com/golang/go/wiki/SliceTricks
 	a = append(a[:idx], append([]*Node{child}, a[idx:]...)...)
@@ -371,7 +495,9 @@ func (node *Node) InsertChild(child *Node, idx int) {
 	node.Children = a
 
 	child.Parent = node
+	nodeClearUnitBases(child)
 	nodeMarkDirtyInternal(node)
+	nodeMarkChildrenStyleDirty(node, child)
 }
 
 func (node *Node) deleteChild(child *Node) *Node {
@@ -392,10 +518,18 @@ func (node *Node) deleteChild(child *Node) *Node {
 
 // RemoveChild removes child node
 func (node *Node) RemoveChild(child *Node) {
-	if node.deleteChild(child) != nil {
+	if node.deleteChild(child) == nil {
+		return
+	}
+	nodeMarkDirtyInternal(node)
+	// a shared child still belongs to its parent in another tree
+	if child.Parent == node {
 		child.Layout = nodeDefaults.Layout // layout is no longer valid
 		child.Parent = nil
-		nodeMarkDirtyInternal(node)
+		nodeClearUnitBases(child)
+		nodeMarkChildrenStyleDirty(node, child)
+	} else {
+		nodeMarkChildrenStyleDirty(node, nil)
 	}
 }
 
@@ -409,8 +543,7 @@ func (node *Node) GetChild(idx int) *Node {
 
 // MarkDirty marks node as dirty
 func (node *Node) MarkDirty() {
-	assertWithNode(node, node.Measure != nil,
-		"Only leaf nodes with custom measure functions should manually mark themselves as dirty")
+	assertWithNode(node, node.Measure != nil, ErrMarkDirtyWithoutMeasure)
 	nodeMarkDirtyInternal(node)
 }
 
@@ -425,10 +558,13 @@ func styleEq(s1, s2 *Style) bool {
 		s1.FlexWrap != s2.FlexWrap ||
 		s1.Overflow != s2.Overflow ||
 		s1.Display != s2.Display ||
+		s1.BoxSizing != s2.BoxSizing ||
+		s1.PointerEvents != s2.PointerEvents ||
 		!feq(s1.Flex, s2.Flex) ||
 		!feq(s1.FlexGrow, s2.FlexGrow) ||
 		!feq(s1.FlexShrink, s2.FlexShrink) ||
-		!valueEq(s1.FlexBasis, s2.FlexBasis) {
+		!valueEq(s1.FlexBasis, s2.FlexBasis) ||
+		!valueEq(s1.FontSize, s2.FontSize) {
 		return false
 	}
 	for i := 0; i < EdgeCount; i++ {
@@ -446,7 +582,12 @@ func styleEq(s1, s2 *Style) bool {
 			return false
 		}
 	}
-	return true
+	for i := 0; i < gutterCount; i++ {
+		if !valueEq(s1.Gap[i], s2.Gap[i]) {
+			return false
+		}
+	}
+	return len(changedCustomProperties(s1, s2)) == 0
 }
 
 // NodeCopyStyle copies style
@@ -524,9 +665,41 @@ func nodeResolveFlexBasisPtr(node *Node) *Value {
 
 // see yoga_props.go
 
-var (
-	currentGenerationCount = 0
-)
+// generationCounter hands out a unique generation to every CalculateLayout
+// call. It is only ever accessed atomically.
+var generationCounter int64
+
+// layoutContext holds the state of a single CalculateLayout call. It is
+// threaded through the recursive layout functions so that layouts of
+// unrelated trees can run concurrently.
+type layoutContext struct {
+	// generationCount forces the recursive routine to visit all dirty nodes
+	// at least once per layout
+	generationCount int
+	// depth is the current recursion depth, used for debug output
+	depth int
+
+	printTree    bool
+	printChanges bool
+	printSkips   bool
+
+	// workers limits the number of goroutines laying out subtrees in
+	// parallel. It's nil if parallel layout is disabled.
+	workers chan struct{}
+}
+
+func newLayoutContext(config *Config) *layoutContext {
+	ctx := &layoutContext{
+		generationCount: int(atomic.AddInt64(&generationCounter, 1)),
+		printTree:       config.PrintTree,
+		printChanges:    config.PrintChanges,
+		printSkips:      config.PrintSkips,
+	}
+	if config.ParallelWorkers > 1 {
+		ctx.workers = make(chan struct{}, config.ParallelWorkers-1)
+	}
+	return ctx
+}
 
 // FloatIsUndefined returns true if value is undefined
 func FloatIsUndefined(value float32) bool {
@@ -543,6 +716,10 @@ func ValueEqual(a Value, b Value) bool {
 		return true
 	}
 
+	if a.Unit == UnitCalc {
+		return calcEqual(a.Calc, b.Calc)
+	}
+
 	return fabs(a.Value-b.Value) < 0.0001
 }
 
@@ -606,38 +783,38 @@ func flexDirectionIsColumn(flexDirection FlexDirection) bool {
 
 func nodeLeadingMargin(node *Node, axis FlexDirection, widthSize float32) float32 {
 	if flexDirectionIsRow(axis) && node.Style.Margin[EdgeStart].Unit != UnitUndefined {
-		return resolveValueMargin(&node.Style.Margin[EdgeStart], widthSize)
+		return nodeResolveValueMargin(node, &node.Style.Margin[EdgeStart], widthSize)
 	}
 
 	v := computedEdgeValue(node.Style.Margin[:], leading[axis], &ValueZero)
-	return resolveValueMargin(v, widthSize)
+	return nodeResolveValueMargin(node, v, widthSize)
 }
 
 func nodeTrailingMargin(node *Node, axis FlexDirection, widthSize float32) float32 {
 	if flexDirectionIsRow(axis) && node.Style.Margin[EdgeEnd].Unit != UnitUndefined {
-		return resolveValueMargin(&node.Style.Margin[EdgeEnd], widthSize)
+		return nodeResolveValueMargin(node, &node.Style.Margin[EdgeEnd], widthSize)
 	}
 
-	return resolveValueMargin(computedEdgeValue(node.Style.Margin[:], trailing[axis], &ValueZero),
+	return nodeResolveValueMargin(node, computedEdgeValue(node.Style.Margin[:], trailing[axis], &ValueZero),
 		widthSize)
 }
 
 func nodeLeadingPadding(node *Node, axis FlexDirection, widthSize float32) float32 {
 	if flexDirectionIsRow(axis) && node.Style.Padding[EdgeStart].Unit != UnitUndefined &&
-		resolveValue(&node.Style.Padding[EdgeStart], widthSize) >= 0 {
-		return resolveValue(&node.Style.Padding[EdgeStart], widthSize)
+		nodeResolveValue(node, &node.Style.Padding[EdgeStart], widthSize) >= 0 {
+		return nodeResolveValue(node, &node.Style.Padding[EdgeStart], widthSize)
 	}
 
-	return fmaxf(resolveValue(computedEdgeValue(node.Style.Padding[:], leading[axis], &ValueZero), widthSize), 0)
+	return fmaxf(nodeResolveValue(node, computedEdgeValue(node.Style.Padding[:], leading[axis], &ValueZero), widthSize), 0)
 }
 
 func nodeTrailingPadding(node *Node, axis FlexDirection, widthSize float32) float32 {
 	if flexDirectionIsRow(axis) && node.Style.Padding[EdgeEnd].Unit != UnitUndefined &&
-		resolveValue(&node.Style.Padding[EdgeEnd], widthSize) >= 0 {
-		return resolveValue(&node.Style.Padding[EdgeEnd], widthSize)
+		nodeResolveValue(node, &node.Style.Padding[EdgeEnd], widthSize) >= 0 {
+		return nodeResolveValue(node, &node.Style.Padding[EdgeEnd], widthSize)
 	}
 
-	return fmaxf(resolveValue(computedEdgeValue(node.Style.Padding[:], trailing[axis], &ValueZero), widthSize), 0)
+	return fmaxf(nodeResolveValue(node, computedEdgeValue(node.Style.Padding[:], trailing[axis], &ValueZero), widthSize), 0)
 }
 
 func nodeLeadingBorder(node *Node, axis FlexDirection) float32 {
@@ -677,6 +854,30 @@ func nodePaddingAndBorderForAxis(node *Node, axis FlexDirection, widthSize float
 		nodeTrailingPaddingAndBorder(node, axis, widthSize)
 }
 
+// nodeResolveBoxSize resolves a width, height, min, max or flex-basis value of
+// node to a border-box size, adding padding and border for content-box nodes.
+func nodeResolveBoxSize(node *Node, value *Value, axis FlexDirection, axisSize float32, widthSize float32) float32 {
+	size := nodeResolveValue(node, value, axisSize)
+	if node.Style.BoxSizing == BoxSizingContentBox && !FloatIsUndefined(size) {
+		size += nodePaddingAndBorderForAxis(node, axis, widthSize)
+	}
+	return size
+}
+
+// nodeGapForAxis returns the space between items along the axis. Column gap
+// separates items laid out in a row, row gap separates items in a column.
+func nodeGapForAxis(node *Node, axis FlexDirection, axisSize float32) float32 {
+	gap := &node.Style.Gap[GutterRow]
+	if flexDirectionIsRow(axis) {
+		gap = &node.Style.Gap[GutterColumn]
+	}
+	if gap.Unit == UnitUndefined {
+		gap = &node.Style.Gap[GutterAll]
+	}
+	// percentage of an indefinite size resolves to 0
+	return fmaxf(nodeResolveValue(node, gap, axisSize), 0)
+}
+
 func nodeAlignItem(node *Node, child *Node) Align {
 	align := child.Style.AlignSelf
 	if child.Style.AlignSelf == AlignAuto {
@@ -702,14 +903,15 @@ func nodeResolveDirection(node *Node, parentDirection Direction) Direction {
 func Baseline(node *Node) float32 {
 	if node.Baseline != nil {
 		baseline := node.Baseline(node, node.Layout.measuredDimensions[DimensionWidth], node.Layout.measuredDimensions[DimensionHeight])
-		assertWithNode(node, !FloatIsUndefined(baseline), "Expect custom baseline function to not return NaN")
+		assertWithNode(node, !FloatIsUndefined(baseline), ErrBaselineNaN)
 		return baseline
 	}
 
 	var baselineChild *Node
-	childCount := len(node.Children)
+	children := nodeLayoutChildren(node)
+	childCount := len(children)
 	for i := 0; i < childCount; i++ {
-		child := node.GetChild(i)
+		child := children[i]
 		if child.lineIndex > 0 {
 			break
 		}
@@ -753,7 +955,7 @@ func flexDirectionCross(flexDirection FlexDirection, direction Direction) FlexDi
 }
 
 func nodeIsFlex(node *Node) bool {
-	return (node.Style.PositionType == PositionTypeRelative &&
+	return (node.Style.PositionType != PositionTypeAbsolute &&
 		(resolveFlexGrow(node) != 0 || nodeResolveFlexShrink(node) != 0))
 }
 
@@ -764,10 +966,11 @@ func isBaselineLayout(node *Node) bool {
 	if node.Style.AlignItems == AlignBaseline {
 		return true
 	}
-	childCount := len(node.Children)
+	children := nodeLayoutChildren(node)
+	childCount := len(children)
 	for i := 0; i < childCount; i++ {
-		child := node.GetChild(i)
-		if child.Style.PositionType == PositionTypeRelative &&
+		child := children[i]
+		if child.Style.PositionType != PositionTypeAbsolute &&
 			child.Style.AlignSelf == AlignBaseline {
 			return true
 		}
@@ -786,7 +989,8 @@ func nodeIsStyleDimDefined(node *Node, axis FlexDirection, parentSize float32) b
 	isNotDefined := (v.Unit == UnitAuto ||
 		v.Unit == UnitUndefined ||
 		(v.Unit == UnitPoint && v.Value < 0) ||
-		(v.Unit == UnitPercent && (v.Value < 0 || FloatIsUndefined(parentSize))))
+		(v.Unit == UnitPercent && (v.Value < 0 || FloatIsUndefined(parentSize))) ||
+		(v.Unit != UnitPoint && v.Unit != UnitPercent && !(nodeResolveValue(node, v, parentSize) >= 0)))
 	return !isNotDefined
 }
 
@@ -796,6 +1000,9 @@ func nodeIsLayoutDimDefined(node *Node, axis FlexDirection) bool {
 }
 
 func nodeIsLeadingPosDefined(node *Node, axis FlexDirection) bool {
+	if node.Style.PositionType == PositionTypeStatic {
+		return false
+	}
 	return (flexDirectionIsRow(axis) &&
 		computedEdgeValue(node.Style.Position[:], EdgeStart, &ValueUndefined).Unit !=
 			UnitUndefined) ||
@@ -804,6 +1011,9 @@ func nodeIsLeadingPosDefined(node *Node, axis FlexDirection) bool {
 }
 
 func nodeIsTrailingPosDefined(node *Node, axis FlexDirection) bool {
+	if node.Style.PositionType == PositionTypeStatic {
+		return false
+	}
 	return (flexDirectionIsRow(axis) &&
 		computedEdgeValue(node.Style.Position[:], EdgeEnd, &ValueUndefined).Unit !=
 			UnitUndefined) ||
@@ -812,10 +1022,13 @@ func nodeIsTrailingPosDefined(node *Node, axis FlexDirection) bool {
 }
 
 func nodeLeadingPosition(node *Node, axis FlexDirection, axisSize float32) float32 {
+	if node.Style.PositionType == PositionTypeStatic {
+		return 0
+	}
 	if flexDirectionIsRow(axis) {
 		leadingPosition := computedEdgeValue(node.Style.Position[:], EdgeStart, &ValueUndefined)
 		if leadingPosition.Unit != UnitUndefined {
-			return resolveValue(leadingPosition, axisSize)
+			return nodeResolveValue(node, leadingPosition, axisSize)
 		}
 	}
 
@@ -824,14 +1037,17 @@ func nodeLeadingPosition(node *Node, axis FlexDirection, axisSize float32) float
 	if leadingPosition.Unit == UnitUndefined {
 		return 0
 	}
-	return resolveValue(leadingPosition, axisSize)
+	return nodeResolveValue(node, leadingPosition, axisSize)
 }
 
 func nodeTrailingPosition(node *Node, axis FlexDirection, axisSize float32) float32 {
+	if node.Style.PositionType == PositionTypeStatic {
+		return 0
+	}
 	if flexDirectionIsRow(axis) {
 		trailingPosition := computedEdgeValue(node.Style.Position[:], EdgeEnd, &ValueUndefined)
 		if trailingPosition.Unit != UnitUndefined {
-			return resolveValue(trailingPosition, axisSize)
+			return nodeResolveValue(node, trailingPosition, axisSize)
 		}
 	}
 
@@ -840,19 +1056,19 @@ func nodeTrailingPosition(node *Node, axis FlexDirection, axisSize float32) floa
 	if trailingPosition.Unit == UnitUndefined {
 		return 0
 	}
-	return resolveValue(trailingPosition, axisSize)
+	return nodeResolveValue(node, trailingPosition, axisSize)
 }
 
-func nodeBoundAxisWithinMinAndMax(node *Node, axis FlexDirection, value float32, axisSize float32) float32 {
+func nodeBoundAxisWithinMinAndMax(node *Node, axis FlexDirection, value float32, axisSize float32, widthSize float32) float32 {
 	min := Undefined
 	max := Undefined
 
 	if flexDirectionIsColumn(axis) {
-		min = resolveValue(&node.Style.MinDimensions[DimensionHeight], axisSize)
-		max = resolveValue(&node.Style.MaxDimensions[DimensionHeight], axisSize)
+		min = nodeResolveBoxSize(node, &node.Style.MinDimensions[DimensionHeight], axis, axisSize, widthSize)
+		max = nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionHeight], axis, axisSize, widthSize)
 	} else if flexDirectionIsRow(axis) {
-		min = resolveValue(&node.Style.MinDimensions[DimensionWidth], axisSize)
-		max = resolveValue(&node.Style.MaxDimensions[DimensionWidth], axisSize)
+		min = nodeResolveBoxSize(node, &node.Style.MinDimensions[DimensionWidth], axis, axisSize, widthSize)
+		max = nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionWidth], axis, axisSize, widthSize)
 	}
 
 	boundValue := value
@@ -886,7 +1102,7 @@ func marginTrailingValue(node *Node, axis FlexDirection) *Value {
 // nodeBoundAxis is like nodeBoundAxisWithinMinAndMax but also ensures that
 // the value doesn't go below the padding and border amount.
 func nodeBoundAxis(node *Node, axis FlexDirection, value float32, axisSize float32, widthSize float32) float32 {
-	return fmaxf(nodeBoundAxisWithinMinAndMax(node, axis, value, axisSize),
+	return fmaxf(nodeBoundAxisWithinMinAndMax(node, axis, value, axisSize, widthSize),
 		nodePaddingAndBorderForAxis(node, axis, widthSize))
 }
 
@@ -906,7 +1122,7 @@ func nodeRelativePosition(node *Node, axis FlexDirection, axisSize float32) floa
 }
 
 func constrainMaxSizeForMode(node *Node, axis FlexDirection, parentAxisSize float32, parentWidth float32, mode *MeasureMode, size *float32) {
-	maxSize := resolveValue(&node.Style.MaxDimensions[dim[axis]], parentAxisSize) +
+	maxSize := nodeResolveBoxSize(node, &node.Style.MaxDimensions[dim[axis]], axis, parentAxisSize, parentWidth) +
 		nodeMarginForAxis(node, axis, parentWidth)
 	switch *mode {
 	case MeasureModeExactly, MeasureModeAtMost:
@@ -956,7 +1172,8 @@ func nodeComputeFlexBasisForChild(node *Node,
 	parentHeight float32,
 	heightMode MeasureMode,
 	direction Direction,
-	config *Config) {
+	config *Config,
+	ctx *layoutContext) {
 	mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
 	isMainAxisRow := flexDirectionIsRow(mainAxis)
 	mainAxisSize := height
@@ -971,26 +1188,29 @@ func nodeComputeFlexBasisForChild(node *Node,
 	var childWidthMeasureMode MeasureMode
 	var childHeightMeasureMode MeasureMode
 
-	resolvedFlexBasis := resolveValue(nodeResolveFlexBasisPtr(child), mainAxisParentSize)
+	resolvedFlexBasis := nodeResolveBoxSize(child, nodeResolveFlexBasisPtr(child), mainAxis, mainAxisParentSize, parentWidth)
+	if isIntrinsicUnit(child.Style.FlexBasis.Unit) {
+		resolvedFlexBasis = nodeIntrinsicSize(child, &child.Style.FlexBasis, mainAxis, parentWidth, parentHeight, direction, config, ctx)
+	}
 	isRowStyleDimDefined := nodeIsStyleDimDefined(child, FlexDirectionRow, parentWidth)
 	isColumnStyleDimDefined := nodeIsStyleDimDefined(child, FlexDirectionColumn, parentHeight)
 
 	if !FloatIsUndefined(resolvedFlexBasis) && !FloatIsUndefined(mainAxisSize) {
 		if FloatIsUndefined(child.Layout.computedFlexBasis) ||
 			(child.Config.IsExperimentalFeatureEnabled(ExperimentalFeatureWebFlexBasis) &&
-				child.Layout.computedFlexBasisGeneration != currentGenerationCount) {
+				child.Layout.computedFlexBasisGeneration != ctx.generationCount) {
 			child.Layout.computedFlexBasis =
 				fmaxf(resolvedFlexBasis, nodePaddingAndBorderForAxis(child, mainAxis, parentWidth))
 		}
 	} else if isMainAxisRow && isRowStyleDimDefined {
 		// The width is definite, so use that as the flex basis.
 		child.Layout.computedFlexBasis =
-			fmaxf(resolveValue(child.resolvedDimensions[DimensionWidth], parentWidth),
+			fmaxf(nodeResolveBoxSize(child, child.resolvedDimensions[DimensionWidth], FlexDirectionRow, parentWidth, parentWidth),
 				nodePaddingAndBorderForAxis(child, FlexDirectionRow, parentWidth))
 	} else if !isMainAxisRow && isColumnStyleDimDefined {
 		// The height is definite, so use that as the flex basis.
 		child.Layout.computedFlexBasis =
-			fmaxf(resolveValue(child.resolvedDimensions[DimensionHeight], parentHeight),
+			fmaxf(nodeResolveBoxSize(child, child.resolvedDimensions[DimensionHeight], FlexDirectionColumn, parentHeight, parentWidth),
 				nodePaddingAndBorderForAxis(child, FlexDirectionColumn, parentWidth))
 	} else {
 		// Compute the flex basis and hypothetical main size (i.e. the clamped
@@ -1005,12 +1225,12 @@ func nodeComputeFlexBasisForChild(node *Node,
 
 		if isRowStyleDimDefined {
 			childWidth =
-				resolveValue(child.resolvedDimensions[DimensionWidth], parentWidth) + marginRow
+				nodeResolveBoxSize(child, child.resolvedDimensions[DimensionWidth], FlexDirectionRow, parentWidth, parentWidth) + marginRow
 			childWidthMeasureMode = MeasureModeExactly
 		}
 		if isColumnStyleDimDefined {
 			childHeight =
-				resolveValue(child.resolvedDimensions[DimensionHeight], parentHeight) + marginColumn
+				nodeResolveBoxSize(child, child.resolvedDimensions[DimensionHeight], FlexDirectionColumn, parentHeight, parentWidth) + marginColumn
 			childHeightMeasureMode = MeasureModeExactly
 		}
 
@@ -1080,17 +1300,18 @@ func nodeComputeFlexBasisForChild(node *Node,
 			parentHeight,
 			false,
 			"measure",
-			config)
+			config,
+			ctx)
 
 		child.Layout.computedFlexBasis =
 			fmaxf(child.Layout.measuredDimensions[dim[mainAxis]],
 				nodePaddingAndBorderForAxis(child, mainAxis, parentWidth))
 	}
 
-	child.Layout.computedFlexBasisGeneration = currentGenerationCount
+	child.Layout.computedFlexBasisGeneration = ctx.generationCount
 }
 
-func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config) {
+func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
 	mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
 	crossAxis := flexDirectionCross(mainAxis, direction)
 	isMainAxisRow := flexDirectionIsRow(mainAxis)
@@ -1104,7 +1325,7 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 	marginColumn := nodeMarginForAxis(child, FlexDirectionColumn, width)
 
 	if nodeIsStyleDimDefined(child, FlexDirectionRow, width) {
-		childWidth = resolveValue(child.resolvedDimensions[DimensionWidth], width) + marginRow
+		childWidth = nodeResolveBoxSize(child, child.resolvedDimensions[DimensionWidth], FlexDirectionRow, width, width) + marginRow
 	} else {
 		// If the child doesn't have a specified width, compute the width based
 		// on the left/right
@@ -1122,7 +1343,7 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 
 	if nodeIsStyleDimDefined(child, FlexDirectionColumn, height) {
 		childHeight =
-			resolveValue(child.resolvedDimensions[DimensionHeight], height) + marginColumn
+			nodeResolveBoxSize(child, child.resolvedDimensions[DimensionHeight], FlexDirectionColumn, height, width) + marginColumn
 	} else {
 		// If the child doesn't have a specified height, compute the height
 		// based on the top/bottom
@@ -1184,7 +1405,8 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 			childHeight,
 			false,
 			"abs-measure",
-			config)
+			config,
+			ctx)
 		childWidth = child.Layout.measuredDimensions[DimensionWidth] +
 			nodeMarginForAxis(child, FlexDirectionRow, width)
 		childHeight = child.Layout.measuredDimensions[DimensionHeight] +
@@ -1201,7 +1423,8 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 		childHeight,
 		true,
 		"abs-layout",
-		config)
+		config,
+		ctx)
 
 	if nodeIsTrailingPosDefined(child, mainAxis) && !nodeIsLeadingPosDefined(child, mainAxis) {
 		axisSize := height
@@ -1249,9 +1472,81 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 	}
 }
 
+// nodeFormsContainingBlock returns true if absolute descendants of node are
+// positioned relative to it. Static nodes are skipped, except for the root.
+func nodeFormsContainingBlock(node *Node) bool {
+	return node.Style.PositionType != PositionTypeStatic || node.Parent == nil
+}
+
+// nodeAbsoluteLayoutDescendants lays out absolute children of static
+// descendants of containingNode against containingNode. offsetLeft and
+// offsetTop are the position of node relative to containingNode.
+func nodeAbsoluteLayoutDescendants(containingNode *Node, node *Node, offsetLeft float32, offsetTop float32, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
+	for _, child := range nodeLayoutChildren(node) {
+		if child.Style.Display == DisplayNone {
+			continue
+		}
+		switch child.Style.PositionType {
+		case PositionTypeStatic:
+			nodeAbsoluteLayoutDescendants(containingNode,
+				child,
+				offsetLeft+child.Layout.Position[EdgeLeft],
+				offsetTop+child.Layout.Position[EdgeTop],
+				width,
+				widthMode,
+				height,
+				direction,
+				config,
+				ctx)
+		case PositionTypeAbsolute:
+			if node != containingNode {
+				nodeAbsoluteLayoutDescendant(containingNode, child, offsetLeft, offsetTop, width, widthMode, height, direction, config, ctx)
+			}
+		}
+	}
+}
+
+func nodeAbsoluteLayoutDescendant(containingNode *Node, child *Node, offsetLeft float32, offsetTop float32, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
+	// The static position was computed by the parent, keep it for axes
+	// without insets.
+	staticLeft := child.Layout.Position[EdgeLeft]
+	staticTop := child.Layout.Position[EdgeTop]
+
+	nodeAbsoluteLayoutChild(containingNode, child, width, widthMode, height, direction, config, ctx)
+
+	for _, axis := range []FlexDirection{resolveFlexDirection(FlexDirectionRow, direction), FlexDirectionColumn} {
+		edge, axisSize, offset, static := EdgeTop, height, offsetTop, staticTop
+		if flexDirectionIsRow(axis) {
+			edge, axisSize, offset, static = EdgeLeft, width, offsetLeft, staticLeft
+		}
+
+		containingSize := containingNode.Layout.measuredDimensions[dim[axis]]
+		size := child.Layout.measuredDimensions[dim[axis]]
+		var position float32
+		if nodeIsLeadingPosDefined(child, axis) {
+			position = nodeLeadingPosition(child, axis, axisSize) +
+				nodeLeadingBorder(containingNode, axis) +
+				nodeLeadingMargin(child, axis, width)
+		} else if nodeIsTrailingPosDefined(child, axis) {
+			position = containingSize - size -
+				nodeTrailingBorder(containingNode, axis) -
+				nodeTrailingMargin(child, axis, width) -
+				nodeTrailingPosition(child, axis, axisSize)
+		} else {
+			child.Layout.Position[edge] = static
+			continue
+		}
+
+		if axis == FlexDirectionRowReverse {
+			position = containingSize - size - position
+		}
+		child.Layout.Position[edge] = position - offset
+	}
+}
+
 // nodeWithMeasureFuncSetMeasuredDimensions sets measure dimensions for node with measure func
 func nodeWithMeasureFuncSetMeasuredDimensions(node *Node, availableWidth float32, availableHeight float32, widthMeasureMode MeasureMode, heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32) {
-	assertWithNode(node, node.Measure != nil, "Expected node to have custom measure function")
+	assertWithNode(node, node.Measure != nil, ErrMissingMeasure)
 
 	paddingAndBorderAxisRow := nodePaddingAndBorderForAxis(node, FlexDirectionRow, availableWidth)
 	paddingAndBorderAxisColumn := nodePaddingAndBorderForAxis(node, FlexDirectionColumn, availableWidth)
@@ -1352,6 +1647,47 @@ func nodeFixedSizeSetMeasuredDimensions(node *Node,
 	return false
 }
 
+// nodeLayoutChildren returns the children that take part in the layout of
+// the node. Children with display: contents are replaced by their own
+// children.
+func nodeLayoutChildren(node *Node) []*Node {
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			return appendLayoutChildren(nil, node)
+		}
+	}
+	return node.Children
+}
+
+func appendLayoutChildren(children []*Node, node *Node) []*Node {
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			children = appendLayoutChildren(children, child)
+		} else {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// nodeCleanupContentsChildren sets an empty layout on children with
+// display: contents. Their own children are laid out by the node.
+func nodeCleanupContentsChildren(node *Node) {
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			child.Layout.Dimensions = [2]float32{0, 0}
+			child.Layout.Position = [4]float32{0, 0, 0, 0}
+			child.Layout.Margin = [6]float32{}
+			child.Layout.Border = [6]float32{}
+			child.Layout.Padding = [6]float32{}
+			child.hasNewLayout = true
+			child.layoutVersion++
+			child.IsDirty = false
+			nodeCleanupContentsChildren(child)
+		}
+	}
+}
+
 // zeroOutLayoutRecursivly zeros out layout recursively
 func zeroOutLayoutRecursivly(node *Node) {
 	node.Layout.Dimensions[DimensionHeight] = 0
@@ -1367,9 +1703,10 @@ func zeroOutLayoutRecursivly(node *Node) {
 	node.Layout.cachedLayout.computedWidth = 0
 	node.Layout.cachedLayout.computedHeight = 0
 	node.hasNewLayout = true
+	node.layoutVersion++
 	childCount := len(node.Children)
 	for i := 0; i < childCount; i++ {
-		child := node.Children[i]
+		child := nodeChild(node, i)
 		zeroOutLayoutRecursivly(child)
 	}
 }
@@ -1461,7 +1798,7 @@ func zeroOutLayoutRecursivly(node *Node) {
 func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	parentDirection Direction, widthMeasureMode MeasureMode,
 	heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32,
-	performLayout bool, config *Config) {
+	performLayout bool, config *Config, ctx *layoutContext) {
 	// assertWithNode(node, YGFloatIsUndefined(availableWidth) ? widthMeasureMode == YGMeasureModeUndefined : true, "availableWidth is indefinite so widthMeasureMode must be YGMeasureModeUndefined");
 	//assertWithNode(node, YGFloatIsUndefined(availableHeight) ? heightMeasureMode == YGMeasureModeUndefined : true, "availableHeight is indefinite so heightMeasureMode must be YGMeasureModeUndefined");
 
@@ -1492,7 +1829,14 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		return
 	}
 
-	childCount := len(node.Children)
+	nodeOwnChildren(node)
+	nodeUpdateChildUnitBases(node, ctx.generationCount)
+	if performLayout {
+		nodeCleanupContentsChildren(node)
+	}
+
+	children := nodeLayoutChildren(node)
+	childCount := len(children)
 	if childCount == 0 {
 		nodeEmptyContainerSetMeasuredDimensions(node, availableWidth, availableHeight, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight)
 		return
@@ -1549,13 +1893,13 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	marginAxisColumn := nodeMarginForAxis(node, FlexDirectionColumn, parentWidth)
 
 	// STEP 2: DETERMINE AVAILABLE SIZE IN MAIN AND CROSS DIRECTIONS
-	minInnerWidth := resolveValue(&node.Style.MinDimensions[DimensionWidth], parentWidth) - marginAxisRow -
+	minInnerWidth := nodeResolveBoxSize(node, &node.Style.MinDimensions[DimensionWidth], FlexDirectionRow, parentWidth, parentWidth) - marginAxisRow -
 		paddingAndBorderAxisRow
-	maxInnerWidth := resolveValue(&node.Style.MaxDimensions[DimensionWidth], parentWidth) - marginAxisRow -
+	maxInnerWidth := nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionWidth], FlexDirectionRow, parentWidth, parentWidth) - marginAxisRow -
 		paddingAndBorderAxisRow
-	minInnerHeight := resolveValue(&node.Style.MinDimensions[DimensionHeight], parentHeight) -
+	minInnerHeight := nodeResolveBoxSize(node, &node.Style.MinDimensions[DimensionHeight], FlexDirectionColumn, parentHeight, parentWidth) -
 		marginAxisColumn - paddingAndBorderAxisColumn
-	maxInnerHeight := resolveValue(&node.Style.MaxDimensions[DimensionHeight], parentHeight) -
+	maxInnerHeight := nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionHeight], FlexDirectionColumn, parentHeight, parentWidth) -
 		marginAxisColumn - paddingAndBorderAxisColumn
 
 	minInnerMainDim := minInnerHeight
@@ -1586,13 +1930,16 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		availableInnerCrossDim = availableInnerHeight
 	}
 
+	mainAxisGap := nodeGapForAxis(node, mainAxis, availableInnerMainDim)
+	crossAxisGap := nodeGapForAxis(node, crossAxis, availableInnerCrossDim)
+
 	// If there is only one child with flexGrow + flexShrink it means we can set the
 	// computedFlexBasis to 0 instead of measuring and shrinking / flexing the child to exactly
 	// match the remaining space
 	var singleFlexChild *Node
 	if measureModeMainDim == MeasureModeExactly {
 		for i := 0; i < childCount; i++ {
-			child := node.GetChild(i)
+			child := children[i]
 			if singleFlexChild != nil {
 				if nodeIsFlex(child) {
 					// There is already a flexible child, abort.
@@ -1606,17 +1953,20 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	}
 
 	var totalOuterFlexBasis float32
+	flexItemCount := 0
 
 	// STEP 3: DETERMINE FLEX BASIS FOR EACH ITEM
 	for i := 0; i < childCount; i++ {
-		child := node.Children[i]
+		child := children[i]
 		if child.Style.Display == DisplayNone {
 			zeroOutLayoutRecursivly(child)
 			child.hasNewLayout = true
+			child.layoutVersion++
 			child.IsDirty = false
 			continue
 		}
 		resolveDimensions(child)
+		nodeResolveIntrinsicDimensions(child, availableInnerWidth, availableInnerHeight, direction, config, ctx)
 		if performLayout {
 			// Set the initial position (relative to the parent).
 			childDirection := nodeResolveDirection(child, direction)
@@ -1642,7 +1992,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			child.NextChild = nil
 		} else {
 			if child == singleFlexChild {
-				child.Layout.computedFlexBasisGeneration = currentGenerationCount
+				child.Layout.computedFlexBasisGeneration = ctx.generationCount
 				child.Layout.computedFlexBasis = 0
 			} else {
 				nodeComputeFlexBasisForChild(node,
@@ -1654,8 +2004,14 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 					availableInnerHeight,
 					heightMeasureMode,
 					direction,
-					config)
+					config,
+					ctx)
 			}
+
+			if flexItemCount > 0 {
+				totalOuterFlexBasis += mainAxisGap
+			}
+			flexItemCount++
 		}
 
 		totalOuterFlexBasis +=
@@ -1686,7 +2042,16 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	// Max main dimension of all the lines.
 	var maxLineMainDim float32
 
+	// Children of the current line whose layout was deferred so that it can
+	// be done in parallel (see Config.ParallelWorkers).
+	var parallelChildren []parallelLayoutChild
+
 	for endOfLineIndex < childCount {
+		// Lines are separated by the gap in the cross axis.
+		if lineCount > 0 {
+			totalLineCrossDim += crossAxisGap
+		}
+
 		// Number of items on the currently line. May be different than the
 		// difference
 		// between start and end indicates because we skip over absolute-positioned
@@ -1709,7 +2074,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 		// Add items to the current line until it's full or we run out of items.
 		for i := startOfLineIndex; i < childCount; i++ {
-			child := node.Children[i]
+			child := children[i]
 			if child.Style.Display == DisplayNone {
 				endOfLineIndex++
 				continue
@@ -1718,8 +2083,12 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 			if child.Style.PositionType != PositionTypeAbsolute {
 				childMarginMainAxis := nodeMarginForAxis(child, mainAxis, availableInnerWidth)
-				flexBasisWithMaxConstraints := fminf(resolveValue(&child.Style.MaxDimensions[dim[mainAxis]], mainAxisParentSize), child.Layout.computedFlexBasis)
-				flexBasisWithMinAndMaxConstraints := fmaxf(resolveValue(&child.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize), flexBasisWithMaxConstraints)
+				// Items on a line are separated by the gap in the main axis.
+				if itemsOnLine > 0 {
+					childMarginMainAxis += mainAxisGap
+				}
+				flexBasisWithMaxConstraints := fminf(nodeResolveBoxSize(child, &child.Style.MaxDimensions[dim[mainAxis]], mainAxis, mainAxisParentSize, availableInnerWidth), child.Layout.computedFlexBasis)
+				flexBasisWithMinAndMaxConstraints := fmaxf(nodeResolveBoxSize(child, &child.Style.MinDimensions[dim[mainAxis]], mainAxis, mainAxisParentSize, availableInnerWidth), flexBasisWithMaxConstraints)
 
 				// If this is a multi-line flow and this item pushes us over the
 				// available size, we've
@@ -1849,10 +2218,10 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			currentRelativeChild = firstRelativeChild
 			for currentRelativeChild != nil {
 				childFlexBasis =
-					fminf(resolveValue(&currentRelativeChild.Style.MaxDimensions[dim[mainAxis]],
-						mainAxisParentSize),
-						fmaxf(resolveValue(&currentRelativeChild.Style.MinDimensions[dim[mainAxis]],
-							mainAxisParentSize),
+					fminf(nodeResolveBoxSize(currentRelativeChild, &currentRelativeChild.Style.MaxDimensions[dim[mainAxis]],
+						mainAxis, mainAxisParentSize, availableInnerWidth),
+						fmaxf(nodeResolveBoxSize(currentRelativeChild, &currentRelativeChild.Style.MinDimensions[dim[mainAxis]],
+							mainAxis, mainAxisParentSize, availableInnerWidth),
 							currentRelativeChild.Layout.computedFlexBasis))
 
 				if remainingFreeSpace < 0 {
@@ -1917,10 +2286,10 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			currentRelativeChild = firstRelativeChild
 			for currentRelativeChild != nil {
 				childFlexBasis =
-					fminf(resolveValue(&currentRelativeChild.Style.MaxDimensions[dim[mainAxis]],
-						mainAxisParentSize),
-						fmaxf(resolveValue(&currentRelativeChild.Style.MinDimensions[dim[mainAxis]],
-							mainAxisParentSize),
+					fminf(nodeResolveBoxSize(currentRelativeChild, &currentRelativeChild.Style.MaxDimensions[dim[mainAxis]],
+						mainAxis, mainAxisParentSize, availableInnerWidth),
+						fmaxf(nodeResolveBoxSize(currentRelativeChild, &currentRelativeChild.Style.MinDimensions[dim[mainAxis]],
+							mainAxis, mainAxisParentSize, availableInnerWidth),
 							currentRelativeChild.Layout.computedFlexBasis))
 				updatedMainSize := childFlexBasis
 
@@ -1985,10 +2354,11 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 						childCrossMeasureMode = MeasureModeUndefined
 					}
 				} else {
-					childCrossSize = resolveValue(currentRelativeChild.resolvedDimensions[dim[crossAxis]],
-						availableInnerCrossDim) +
+					childCrossSize = nodeResolveBoxSize(currentRelativeChild, currentRelativeChild.resolvedDimensions[dim[crossAxis]],
+						crossAxis, availableInnerCrossDim, availableInnerWidth) +
 						marginCross
-					isLoosePercentageMeasurement := currentRelativeChild.resolvedDimensions[dim[crossAxis]].Unit == UnitPercent &&
+					crossDimValue := currentRelativeChild.resolvedDimensions[dim[crossAxis]]
+					isLoosePercentageMeasurement := (crossDimValue.Unit == UnitPercent || (crossDimValue.Unit == UnitCalc && crossDimValue.Calc.hasPercent())) &&
 						measureModeCrossDim != MeasureModeExactly
 					childCrossMeasureMode = MeasureModeExactly
 					if FloatIsUndefined(childCrossSize) || isLoosePercentageMeasurement {
@@ -2052,24 +2422,55 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 					childHeightMeasureMode = childMainMeasureMode
 				}
 
-				// Recursively call the layout algorithm for this child with the updated
-				// main size.
-				layoutNodeInternal(currentRelativeChild,
-					childWidth,
-					childHeight,
+				childPerformLayout := performLayout && !requiresStretchLayout
+
+				if ctx.workers != nil && childPerformLayout &&
+					childWidthMeasureMode == MeasureModeExactly &&
+					childHeightMeasureMode == MeasureModeExactly {
+					// The size of this child is fully determined, so laying out its
+					// subtree doesn't depend on any of its siblings. Defer it so
+					// that it can run in parallel with the others.
+					parallelChildren = append(parallelChildren, parallelLayoutChild{
+						node:   currentRelativeChild,
+						width:  childWidth,
+						height: childHeight,
+					})
+				} else {
+					// Recursively call the layout algorithm for this child with the updated
+					// main size.
+					layoutNodeInternal(currentRelativeChild,
+						childWidth,
+						childHeight,
+						direction,
+						childWidthMeasureMode,
+						childHeightMeasureMode,
+						availableInnerWidth,
+						availableInnerHeight,
+						childPerformLayout,
+						"flex",
+						config,
+						ctx)
+					if currentRelativeChild.Layout.HadOverflow {
+						node.Layout.HadOverflow = true
+					}
+				}
+
+				currentRelativeChild = currentRelativeChild.NextChild
+			}
+
+			if len(parallelChildren) > 0 {
+				layoutChildrenParallel(parallelChildren,
 					direction,
-					childWidthMeasureMode,
-					childHeightMeasureMode,
 					availableInnerWidth,
 					availableInnerHeight,
-					performLayout && !requiresStretchLayout,
-					"flex",
-					config)
-				if currentRelativeChild.Layout.HadOverflow {
-					node.Layout.HadOverflow = true
+					config,
+					ctx)
+				for _, c := range parallelChildren {
+					if c.node.Layout.HadOverflow {
+						node.Layout.HadOverflow = true
+					}
 				}
-
-				currentRelativeChild = currentRelativeChild.NextChild
+				parallelChildren = parallelChildren[:0]
 			}
 		}
 
@@ -2092,10 +2493,10 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 		if measureModeMainDim == MeasureModeAtMost && remainingFreeSpace > 0 {
 			if node.Style.MinDimensions[dim[mainAxis]].Unit != UnitUndefined &&
-				resolveValue(&node.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize) >= 0 {
+				nodeResolveValue(node, &node.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize) >= 0 {
 				remainingFreeSpace =
 					fmaxf(0,
-						resolveValue(&node.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize)-
+						nodeResolveValue(node, &node.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize)-
 							(availableInnerMainDim-remainingFreeSpace))
 			} else {
 				remainingFreeSpace = 0
@@ -2104,8 +2505,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 		numberOfAutoMarginsOnCurrentLine := 0
 		for i := startOfLineIndex; i < endOfLineIndex; i++ {
-			child := node.Children[i]
-			if child.Style.PositionType == PositionTypeRelative {
+			child := children[i]
+			if child.Style.PositionType != PositionTypeAbsolute {
 				if marginLeadingValue(child, mainAxis).Unit == UnitAuto {
 					numberOfAutoMarginsOnCurrentLine++
 				}
@@ -2131,15 +2532,23 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				// Space on the edges is half of the space between elements
 				betweenMainDim = remainingFreeSpace / float32(itemsOnLine)
 				leadingMainDim = betweenMainDim / 2
+			case JustifySpaceEvenly:
+				// Space on the edges is the same as the space between elements
+				betweenMainDim = remainingFreeSpace / float32(itemsOnLine+1)
+				leadingMainDim = betweenMainDim
 			case JustifyFlexStart:
 			}
 		}
 
+		// Gap is added after every item, the one after the last item is
+		// removed once the line is placed.
+		betweenMainDim += mainAxisGap
+
 		mainDim := leadingPaddingAndBorderMain + leadingMainDim
 		var crossDim float32
 
 		for i := startOfLineIndex; i < endOfLineIndex; i++ {
-			child := node.Children[i]
+			child := children[i]
 			if child.Style.Display == DisplayNone {
 				continue
 			}
@@ -2158,7 +2567,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				// Now that we placed the element, we need to update the variables.
 				// We need to do that only for relative elements. Absolute elements
 				// do not take part in that phase.
-				if child.Style.PositionType == PositionTypeRelative {
+				if child.Style.PositionType != PositionTypeAbsolute {
 					if marginLeadingValue(child, mainAxis).Unit == UnitAuto {
 						mainDim += remainingFreeSpace / float32(numberOfAutoMarginsOnCurrentLine)
 					}
@@ -2193,6 +2602,9 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			}
 		}
 
+		if itemsOnLine > 0 {
+			mainDim -= mainAxisGap
+		}
 		mainDim += trailingPaddingAndBorderMain
 
 		containerCrossAxis := availableInnerCrossDim
@@ -2224,7 +2636,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		// We can skip child alignment if we're just measuring the container.
 		if performLayout {
 			for i := startOfLineIndex; i < endOfLineIndex; i++ {
-				child := node.Children[i]
+				child := children[i]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
@@ -2318,7 +2730,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 								availableInnerHeight,
 								true,
 								"stretch",
-								config)
+								config,
+								ctx)
 						}
 					} else {
 						remainingCrossDim := containerCrossAxis - nodeDimWithMargin(child, crossAxis, availableInnerWidth)
@@ -2382,6 +2795,15 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			if availableInnerCrossDim > totalLineCrossDim && lineCount > 1 {
 				crossDimLead = remainingAlignContentDim / float32(lineCount-1)
 			}
+		case AlignSpaceEvenly:
+			if availableInnerCrossDim > totalLineCrossDim {
+				currentLead += remainingAlignContentDim / float32(lineCount+1)
+				if lineCount > 1 {
+					crossDimLead = remainingAlignContentDim / float32(lineCount+1)
+				}
+			} else {
+				currentLead += remainingAlignContentDim / 2
+			}
 		case AlignAuto:
 		case AlignFlexStart:
 		case AlignBaseline:
@@ -2392,16 +2814,20 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			startIndex := endIndex
 			var ii int
 
+			if i > 0 {
+				currentLead += crossAxisGap
+			}
+
 			// compute the line's height and find the endIndex
 			var lineHeight float32
 			var maxAscentForCurrentLine float32
 			var maxDescentForCurrentLine float32
 			for ii = startIndex; ii < childCount; ii++ {
-				child := node.Children[ii]
+				child := children[ii]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
-				if child.Style.PositionType == PositionTypeRelative {
+				if child.Style.PositionType != PositionTypeAbsolute {
 					if child.lineIndex != i {
 						break
 					}
@@ -2424,11 +2850,11 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 			if performLayout {
 				for ii = startIndex; ii < endIndex; ii++ {
-					child := node.Children[ii]
+					child := children[ii]
 					if child.Style.Display == DisplayNone {
 						continue
 					}
-					if child.Style.PositionType == PositionTypeRelative {
+					if child.Style.PositionType != PositionTypeAbsolute {
 						switch nodeAlignItem(node, child) {
 						case AlignFlexStart:
 							{
@@ -2481,7 +2907,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 											availableInnerHeight,
 											true,
 											"multiline-stretch",
-											config)
+											config,
+											ctx)
 									}
 								}
 							}
@@ -2494,6 +2921,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 						case AlignAuto:
 						case AlignSpaceBetween:
 						case AlignSpaceAround:
+						case AlignSpaceEvenly:
 						}
 					}
 				}
@@ -2521,7 +2949,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		node.Style.Overflow == OverflowScroll {
 		node.Layout.measuredDimensions[dim[mainAxis]] = fmaxf(
 			fminf(availableInnerMainDim+paddingAndBorderAxisMain,
-				nodeBoundAxisWithinMinAndMax(node, mainAxis, maxLineMainDim, mainAxisParentSize)),
+				nodeBoundAxisWithinMinAndMax(node, mainAxis, maxLineMainDim, mainAxisParentSize, parentWidth)),
 			paddingAndBorderAxisMain)
 	}
 
@@ -2542,15 +2970,16 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				nodeBoundAxisWithinMinAndMax(node,
 					crossAxis,
 					totalLineCrossDim+paddingAndBorderAxisCross,
-					crossAxisParentSize)),
+					crossAxisParentSize,
+					parentWidth)),
 				paddingAndBorderAxisCross)
 	}
 
 	// As we only wrapped in normal direction yet, we need to reverse the positions on wrap-reverse.
 	if performLayout && node.Style.FlexWrap == WrapWrapReverse {
 		for i := 0; i < childCount; i++ {
-			child := node.GetChild(i)
-			if child.Style.PositionType == PositionTypeRelative {
+			child := children[i]
+			if child.Style.PositionType != PositionTypeAbsolute {
 				child.Layout.Position[pos[crossAxis]] = node.Layout.measuredDimensions[dim[crossAxis]] -
 					child.Layout.Position[pos[crossAxis]] -
 					child.Layout.measuredDimensions[dim[crossAxis]]
@@ -2560,19 +2989,26 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 	if performLayout {
 		// STEP 10: SIZING AND POSITIONING ABSOLUTE CHILDREN
+		absoluteWidthMode := measureModeCrossDim
+		if isMainAxisRow {
+			absoluteWidthMode = measureModeMainDim
+		}
+
+		// Absolute children of a static node are positioned by the nearest
+		// ancestor that forms a containing block.
+		if !nodeFormsContainingBlock(node) {
+			firstAbsoluteChild = nil
+		}
 		for currentAbsoluteChild = firstAbsoluteChild; currentAbsoluteChild != nil; currentAbsoluteChild = currentAbsoluteChild.NextChild {
-			mode := measureModeCrossDim
-			if isMainAxisRow {
-				mode = measureModeMainDim
-			}
 
 			nodeAbsoluteLayoutChild(node,
 				currentAbsoluteChild,
 				availableInnerWidth,
-				mode,
+				absoluteWidthMode,
 				availableInnerHeight,
 				direction,
-				config)
+				config,
+				ctx)
 		}
 
 		// STEP 11: SETTING TRAILING POSITIONS FOR CHILDREN
@@ -2582,7 +3018,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		// Set trailing position if necessary.
 		if needsMainTrailingPos || needsCrossTrailingPos {
 			for i := 0; i < childCount; i++ {
-				child := node.Children[i]
+				child := children[i]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
@@ -2595,16 +3031,22 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				}
 			}
 		}
+
+		if nodeFormsContainingBlock(node) {
+			nodeAbsoluteLayoutDescendants(node,
+				node,
+				0,
+				0,
+				availableInnerWidth,
+				absoluteWidthMode,
+				availableInnerHeight,
+				direction,
+				config,
+				ctx)
+		}
 	}
 }
 
-var (
-	gDepth        = 0
-	gPrintTree    = false
-	gPrintChanges = false
-	gPrintSkips   = false
-)
-
 const (
 	spacerStr = "                                                            "
 )
@@ -2730,13 +3172,13 @@ func nodeCanUseCachedMeasurement(widthMode MeasureMode, width float32, heightMod
 func layoutNodeInternal(node *Node, availableWidth float32, availableHeight float32,
 	parentDirection Direction, widthMeasureMode MeasureMode,
 	heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32,
-	performLayout bool, reason string, config *Config) bool {
+	performLayout bool, reason string, config *Config, ctx *layoutContext) bool {
 	layout := &node.Layout
 
-	gDepth++
+	ctx.depth++
 
 	needToVisitNode :=
-		(node.IsDirty && layout.generationCount != currentGenerationCount) ||
+		(node.IsDirty && layout.generationCount != ctx.generationCount) ||
 			layout.lastParentDirection != parentDirection
 
 	if needToVisitNode {
@@ -2825,12 +3267,12 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 		layout.measuredDimensions[DimensionWidth] = cachedResults.computedWidth
 		layout.measuredDimensions[DimensionHeight] = cachedResults.computedHeight
 
-		if gPrintChanges && gPrintSkips {
-			fmt.Printf("%s%d.{[skipped] ", spacer(gDepth), gDepth)
+		if ctx.printChanges && ctx.printSkips {
+			log(node, LogLevelVerbose, "%s%d.{[skipped] ", spacer(ctx.depth), ctx.depth)
 			if node.Print != nil {
 				node.Print(node)
 			}
-			fmt.Printf("wm: %s, hm: %s, aw: %f ah: %f => d: (%f, %f) %s\n",
+			log(node, LogLevelVerbose, "wm: %s, hm: %s, aw: %f ah: %f => d: (%f, %f) %s\n",
 				measureModeName(widthMeasureMode, performLayout),
 				measureModeName(heightMeasureMode, performLayout),
 				availableWidth,
@@ -2840,16 +3282,16 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 				reason)
 		}
 	} else {
-		if gPrintChanges {
+		if ctx.printChanges {
 			s := ""
 			if needToVisitNode {
 				s = "*"
 			}
-			fmt.Printf("%s%d.{%s", spacer(gDepth), gDepth, s)
+			log(node, LogLevelVerbose, "%s%d.{%s", spacer(ctx.depth), ctx.depth, s)
 			if node.Print != nil {
 				node.Print(node)
 			}
-			fmt.Printf("wm: %s, hm: %s, aw: %f ah: %f %s\n",
+			log(node, LogLevelVerbose, "wm: %s, hm: %s, aw: %f ah: %f %s\n",
 				measureModeName(widthMeasureMode, performLayout),
 				measureModeName(heightMeasureMode, performLayout),
 				availableWidth,
@@ -2866,18 +3308,19 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 			parentWidth,
 			parentHeight,
 			performLayout,
-			config)
+			config,
+			ctx)
 
-		if gPrintChanges {
+		if ctx.printChanges {
 			s := ""
 			if needToVisitNode {
 				s = "*"
 			}
-			fmt.Printf("%s%d.}%s", spacer(gDepth), gDepth, s)
+			log(node, LogLevelVerbose, "%s%d.}%s", spacer(ctx.depth), ctx.depth, s)
 			if node.Print != nil {
 				node.Print(node)
 			}
-			fmt.Printf("wm: %s, hm: %s, d: (%f, %f) %s\n",
+			log(node, LogLevelVerbose, "wm: %s, hm: %s, d: (%f, %f) %s\n",
 				measureModeName(widthMeasureMode, performLayout),
 				measureModeName(heightMeasureMode, performLayout),
 				layout.measuredDimensions[DimensionWidth],
@@ -2889,8 +3332,8 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 
 		if cachedResults == nil {
 			if layout.nextCachedMeasurementsIndex == maxCachedResultCount {
-				if gPrintChanges {
-					fmt.Printf("Out of cache entries!\n")
+				if ctx.printChanges {
+					log(node, LogLevelVerbose, "Out of cache entries!\n")
 				}
 				layout.nextCachedMeasurementsIndex = 0
 			}
@@ -2918,17 +3361,18 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 		node.Layout.Dimensions[DimensionWidth] = node.Layout.measuredDimensions[DimensionWidth]
 		node.Layout.Dimensions[DimensionHeight] = node.Layout.measuredDimensions[DimensionHeight]
 		node.hasNewLayout = true
+		node.layoutVersion++
 		node.IsDirty = false
 	}
 
-	gDepth--
-	layout.generationCount = currentGenerationCount
+	ctx.depth--
+	layout.generationCount = ctx.generationCount
 	return needToVisitNode || cachedResults == nil
 }
 
 // SetPointScaleFactor sets scale factor
 func (config *Config) SetPointScaleFactor(pixelsInPoint float32) {
-	assertWithConfig(config, pixelsInPoint >= 0, "Scale factor should not be less than zero")
+	assertWithConfig(config, pixelsInPoint >= 0, ErrNegativeScaleFactor)
 
 	// We store points for Pixel as we will use it for rounding
 	if pixelsInPoint == 0 {
@@ -2986,18 +3430,21 @@ func roundToPixelGrid(node *Node, pointScaleFactor float32, absoluteLeft float32
 			roundValueToPixelGrid(absoluteNodeTop, pointScaleFactor, false, textRounding)
 
 	for _, child := range node.Children {
-		roundToPixelGrid(child, pointScaleFactor, absoluteNodeLeft, absoluteNodeTop)
+		// shared children were not laid out and keep their rounded layout
+		if child.Parent == node {
+			roundToPixelGrid(child, pointScaleFactor, absoluteNodeLeft, absoluteNodeTop)
+		}
 	}
 }
 
 func calcStartWidth(node *Node, parentWidth float32) (float32, MeasureMode) {
 	if nodeIsStyleDimDefined(node, FlexDirectionRow, parentWidth) {
-		width := resolveValue(node.resolvedDimensions[dim[FlexDirectionRow]], parentWidth)
+		width := nodeResolveBoxSize(node, node.resolvedDimensions[dim[FlexDirectionRow]], FlexDirectionRow, parentWidth, parentWidth)
 		margin := nodeMarginForAxis(node, FlexDirectionRow, parentWidth)
 		return width + margin, MeasureModeExactly
 	}
-	if resolveValue(&node.Style.MaxDimensions[DimensionWidth], parentWidth) >= 0.0 {
-		width := resolveValue(&node.Style.MaxDimensions[DimensionWidth], parentWidth)
+	if nodeResolveValue(node, &node.Style.MaxDimensions[DimensionWidth], parentWidth) >= 0.0 {
+		width := nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionWidth], FlexDirectionRow, parentWidth, parentWidth)
 		return width, MeasureModeAtMost
 	}
 
@@ -3010,12 +3457,12 @@ func calcStartWidth(node *Node, parentWidth float32) (float32, MeasureMode) {
 }
 func calcStartHeight(node *Node, parentWidth, parentHeight float32) (float32, MeasureMode) {
 	if nodeIsStyleDimDefined(node, FlexDirectionColumn, parentHeight) {
-		height := resolveValue(node.resolvedDimensions[dim[FlexDirectionColumn]], parentHeight)
+		height := nodeResolveBoxSize(node, node.resolvedDimensions[dim[FlexDirectionColumn]], FlexDirectionColumn, parentHeight, parentWidth)
 		margin := nodeMarginForAxis(node, FlexDirectionColumn, parentWidth)
 		return height + margin, MeasureModeExactly
 	}
-	if resolveValue(&node.Style.MaxDimensions[DimensionHeight], parentHeight) >= 0 {
-		height := resolveValue(&node.Style.MaxDimensions[DimensionHeight], parentHeight)
+	if nodeResolveValue(node, &node.Style.MaxDimensions[DimensionHeight], parentHeight) >= 0 {
+		height := nodeResolveBoxSize(node, &node.Style.MaxDimensions[DimensionHeight], FlexDirectionColumn, parentHeight, parentWidth)
 		return height, MeasureModeAtMost
 	}
 	height := parentHeight
@@ -3026,27 +3473,45 @@ func calcStartHeight(node *Node, parentWidth, parentHeight float32) (float32, Me
 	return height, heightMeasureMode
 }
 
-// CalculateLayout calculates layout
+// CalculateLayout calculates layout.
+// It is safe to call concurrently on trees that don't share any nodes.
 func CalculateLayout(node *Node, parentWidth float32, parentHeight float32, parentDirection Direction) {
-	// Increment the generation count. This will force the recursive routine to
+	// Apply Config.Stylesheet to the nodes inserted or removed since the
+	// last layout
+	nodeRestyleDirty(node)
+
+	// Get a new generation count. This will force the recursive routine to
 	// visit
 	// all dirty nodes at least once. Subsequent visits will be skipped if the
 	// input
 	// parameters don't change.
-	currentGenerationCount++
+	ctx := newLayoutContext(node.Config)
+
+	// Layouts cached for another viewport may depend on viewport units
+	if !FloatsEqual(node.viewport[DimensionWidth], parentWidth) ||
+		!FloatsEqual(node.viewport[DimensionHeight], parentHeight) {
+		node.viewport = [2]float32{parentWidth, parentHeight}
+		nodeMarkViewportDependentDirty(node)
+	}
+	node.bases = nodeComputeUnitBases(node)
+	node.bases.generation = ctx.generationCount
+
+	node.bases = nodeComputeUnitBases(node)
+	node.bases.generation = ctx.generationCount
 
 	resolveDimensions(node)
+	nodeResolveIntrinsicDimensions(node, parentWidth, parentHeight, parentDirection, node.Config, ctx)
 
 	width, widthMeasureMode := calcStartWidth(node, parentWidth)
 	height, heightMeasureMode := calcStartHeight(node, parentWidth, parentHeight)
 
 	if layoutNodeInternal(node, width, height, parentDirection,
 		widthMeasureMode, heightMeasureMode, parentWidth, parentHeight,
-		true, "initial", node.Config) {
+		true, "initial", node.Config, ctx) {
 		nodeSetPosition(node, node.Layout.Direction, parentWidth, parentHeight, parentWidth)
 		roundToPixelGrid(node, node.Config.PointScaleFactor, 0, 0)
 
-		if gPrintTree {
+		if ctx.printTree {
 			NodePrint(node, PrintOptionsLayout|PrintOptionsChildren|PrintOptionsStyle)
 		}
 	}
@@ -3063,7 +3528,23 @@ func (config *Config) IsExperimentalFeatureEnabled(feature ExperimentalFeature)
 }
 
 func log(node *Node, level LogLevel, format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	config := &configDefaults
+	if node != nil && node.Config != nil {
+		config = node.Config
+	}
+	logWithConfig(config, node, level, format, args...)
+}
+
+func logWithConfig(config *Config, node *Node, level LogLevel, format string, args ...interface{}) {
+	if config.Logger == nil || !config.logLevelEnabled(level) {
+		return
+	}
+	config.Logger(config, node, level, format, args...)
+}
+
+// logLevelEnabled returns true if messages at level should be logged
+func (config *Config) logLevelEnabled(level LogLevel) bool {
+	return level == LogLevelFatal || level <= config.LogLevel
 }
 
 func assertCond(cond bool, format string, args ...interface{}) {
@@ -3072,12 +3553,14 @@ func assertCond(cond bool, format string, args ...interface{}) {
 	}
 }
 
-func assertWithNode(node *Node, cond bool, format string, args ...interface{}) {
-	assertCond(cond, format, args...)
+func assertWithNode(node *Node, cond bool, err error) {
+	if !cond {
+		panic(&NodeError{Node: node, Err: err})
+	}
 }
 
-func assertWithConfig(config *Config, condition bool, message string) {
+func assertWithConfig(config *Config, condition bool, err error) {
 	if !condition {
-		panic(message)
+		panic(&ConfigError{Config: config, Err: err})
 	}
 }
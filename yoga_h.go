@@ -11,17 +11,20 @@ type Size struct {
 	Height float32
 }
 
-// Value describes value
+// Value describes value. Since Calc was added, Value literals need field
+// names, like Value{Value: 10, Unit: UnitPoint}.
 type Value struct {
 	Value float32
 	Unit  Unit
+	// Calc is the expression of a value with UnitCalc
+	Calc *Calc
 }
 
 var (
 	// ValueUndefined defines undefined YGValue
-	ValueUndefined = Value{Undefined, UnitUndefined}
+	ValueUndefined = Value{Value: Undefined, Unit: UnitUndefined}
 	// ValueAuto defines auto YGValue
-	ValueAuto = Value{Undefined, UnitAuto}
+	ValueAuto = Value{Value: Undefined, Unit: UnitAuto}
 )
 
 // MeasureFunc describes function for measuring
@@ -30,6 +33,10 @@ type MeasureFunc func(node *Node, width float32, widthMode MeasureMode, height f
 // BaselineFunc describes function for baseline
 type BaselineFunc func(node *Node, width float32, height float32) float32
 
+// CloneNodeFunc clones oldNode, child childIndex of owner, when a copy-on-write
+// layout needs to change it
+type CloneNodeFunc func(oldNode *Node, owner *Node, childIndex int) *Node
+
 // PrintFunc defines function for printing
 type PrintFunc func(node *Node)
 
@@ -19,6 +19,7 @@ YG_NODE_STYLE_PROPERTY_IMPL(YGPositionType, PositionType, positionType, position
 YG_NODE_STYLE_PROPERTY_IMPL(YGWrap, FlexWrap, flexWrap, flexWrap);
 YG_NODE_STYLE_PROPERTY_IMPL(YGOverflow, Overflow, overflow, overflow);
 YG_NODE_STYLE_PROPERTY_IMPL(YGDisplay, Display, display, display);
+YG_NODE_STYLE_PROPERTY_IMPL(YGBoxSizing, BoxSizing, boxSizing, boxSizing);
 
 YG_NODE_STYLE_PROPERTY_IMPL(float, Flex, flex, flex);
 YG_NODE_STYLE_PROPERTY_SETTER_IMPL(float, FlexGrow, flexGrow, flexGrow);
@@ -30,9 +31,14 @@ YG_NODE_STYLE_EDGE_PROPERTY_UNIT_IMPL(YGValue, Margin, margin, margin);
 YG_NODE_STYLE_EDGE_PROPERTY_UNIT_AUTO_IMPL(YGValue, Margin, margin);
 YG_NODE_STYLE_EDGE_PROPERTY_UNIT_IMPL(YGValue, Padding, padding, padding);
 YG_NODE_STYLE_EDGE_PROPERTY_IMPL(float, Border, border, border);
+YG_NODE_STYLE_GUTTER_PROPERTY_UNIT_IMPL(YGValue, Gap, gap, gap);
 
 YG_NODE_STYLE_PROPERTY_UNIT_AUTO_IMPL(YGValue, Width, width, dimensions[YGDimensionWidth]);
 YG_NODE_STYLE_PROPERTY_UNIT_AUTO_IMPL(YGValue, Height, height, dimensions[YGDimensionHeight]);
+YG_NODE_STYLE_PROPERTY_UNIT_INTRINSIC_IMPL(YGValue, Width, width, dimensions[YGDimensionWidth]);
+YG_NODE_STYLE_PROPERTY_UNIT_INTRINSIC_IMPL(YGValue, Height, height, dimensions[YGDimensionHeight]);
+YG_NODE_STYLE_PROPERTY_UNIT_INTRINSIC_IMPL(YGValue, FlexBasis, flexBasis, flexBasis);
+YG_NODE_STYLE_PROPERTY_UNIT_IMPL(YGValue, FontSize, fontSize, fontSize);
 YG_NODE_STYLE_PROPERTY_UNIT_IMPL(YGValue, MinWidth, minWidth, minDimensions[YGDimensionWidth]);
 YG_NODE_STYLE_PROPERTY_UNIT_IMPL(YGValue, MinHeight, minHeight, minDimensions[YGDimensionHeight]);
 YG_NODE_STYLE_PROPERTY_UNIT_IMPL(YGValue, MaxWidth, maxWidth, maxDimensions[YGDimensionWidth]);
@@ -81,6 +87,24 @@ func (node *Node) StyleSetWidthPercent(width float32) {
 	}
 }
 
+// StyleSetWidthCalc sets width to a calc expression
+func (node *Node) StyleSetWidthCalc(calc *Calc) {
+	value := &node.Style.Dimensions[DimensionWidth]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetWidthValue sets width in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetWidthValue(width Value) {
+	value := &node.Style.Dimensions[DimensionWidth]
+	if !valueEq(*value, width) {
+		*value = width
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleSetWidthAuto sets width auto
 func (node *Node) StyleSetWidthAuto() {
 	dim := &node.Style.Dimensions[DimensionWidth]
@@ -91,6 +115,37 @@ func (node *Node) StyleSetWidthAuto() {
 	}
 }
 
+// StyleSetWidthMinContent sets width to min-content
+func (node *Node) StyleSetWidthMinContent() {
+	dim := &node.Style.Dimensions[DimensionWidth]
+	if dim.Unit != UnitMinContent {
+		dim.Value = Undefined
+		dim.Unit = UnitMinContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetWidthMaxContent sets width to max-content
+func (node *Node) StyleSetWidthMaxContent() {
+	dim := &node.Style.Dimensions[DimensionWidth]
+	if dim.Unit != UnitMaxContent {
+		dim.Value = Undefined
+		dim.Unit = UnitMaxContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetWidthFitContent sets width to fit-content(limit). Undefined limit
+// fits the content to the available space.
+func (node *Node) StyleSetWidthFitContent(limit float32) {
+	dim := &node.Style.Dimensions[DimensionWidth]
+	if dim.Unit != UnitFitContent || !FloatsEqual(dim.Value, limit) {
+		dim.Value = limit
+		dim.Unit = UnitFitContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetWidth gets width
 func (node *Node) StyleGetWidth() Value {
 	return node.Style.Dimensions[DimensionWidth]
@@ -122,6 +177,24 @@ func (node *Node) StyleSetHeightPercent(height float32) {
 	}
 }
 
+// StyleSetHeightCalc sets height to a calc expression
+func (node *Node) StyleSetHeightCalc(calc *Calc) {
+	value := &node.Style.Dimensions[DimensionHeight]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetHeightValue sets height in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetHeightValue(height Value) {
+	value := &node.Style.Dimensions[DimensionHeight]
+	if !valueEq(*value, height) {
+		*value = height
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleSetHeightAuto sets height auto
 func (node *Node) StyleSetHeightAuto() {
 	dim := &node.Style.Dimensions[DimensionHeight]
@@ -132,6 +205,37 @@ func (node *Node) StyleSetHeightAuto() {
 	}
 }
 
+// StyleSetHeightMinContent sets height to min-content
+func (node *Node) StyleSetHeightMinContent() {
+	dim := &node.Style.Dimensions[DimensionHeight]
+	if dim.Unit != UnitMinContent {
+		dim.Value = Undefined
+		dim.Unit = UnitMinContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetHeightMaxContent sets height to max-content
+func (node *Node) StyleSetHeightMaxContent() {
+	dim := &node.Style.Dimensions[DimensionHeight]
+	if dim.Unit != UnitMaxContent {
+		dim.Value = Undefined
+		dim.Unit = UnitMaxContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetHeightFitContent sets height to fit-content(limit). Undefined limit
+// fits the content to the available space.
+func (node *Node) StyleSetHeightFitContent(limit float32) {
+	dim := &node.Style.Dimensions[DimensionHeight]
+	if dim.Unit != UnitFitContent || !FloatsEqual(dim.Value, limit) {
+		dim.Value = limit
+		dim.Unit = UnitFitContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetHeight gets height
 func (node *Node) StyleGetHeight() Value {
 	return node.Style.Dimensions[DimensionHeight]
@@ -145,6 +249,14 @@ func (node *Node) StyleSetPositionType(positionType PositionType) {
 	}
 }
 
+// StyleSetBoxSizing sets box sizing
+func (node *Node) StyleSetBoxSizing(boxSizing BoxSizing) {
+	if node.Style.BoxSizing != boxSizing {
+		node.Style.BoxSizing = boxSizing
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleSetPosition sets position
 func (node *Node) StyleSetPosition(edge Edge, position float32) {
 	pos := &node.Style.Position[edge]
@@ -171,6 +283,24 @@ func (node *Node) StyleSetPositionPercent(edge Edge, position float32) {
 	}
 }
 
+// StyleSetPositionCalc sets position to a calc expression
+func (node *Node) StyleSetPositionCalc(edge Edge, calc *Calc) {
+	value := &node.Style.Position[edge]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetPositionValue sets position in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetPositionValue(edge Edge, position Value) {
+	value := &node.Style.Position[edge]
+	if !valueEq(*value, position) {
+		*value = position
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetPosition gets position
 func (node *Node) StyleGetPosition(edge Edge) Value {
 	return node.Style.Position[edge]
@@ -240,6 +370,17 @@ func (node *Node) StyleSetOverflow(overflow Overflow) {
 	}
 }
 
+// StyleSetPointerEvents sets pointer events. It doesn't change the layout,
+// so the node isn't marked dirty.
+func (node *Node) StyleSetPointerEvents(pointerEvents PointerEvents) {
+	node.Style.PointerEvents = pointerEvents
+}
+
+// StyleGetPointerEvents gets pointer events
+func (node *Node) StyleGetPointerEvents() PointerEvents {
+	return node.Style.PointerEvents
+}
+
 // StyleSetDisplay sets display
 func (node *Node) StyleSetDisplay(display Display) {
 	if node.Style.Display != display {
@@ -298,6 +439,24 @@ func (node *Node) StyleSetFlexBasisPercent(flexBasis float32) {
 	}
 }
 
+// StyleSetFlexBasisCalc sets flex basis to a calc expression
+func (node *Node) StyleSetFlexBasisCalc(calc *Calc) {
+	value := &node.Style.FlexBasis
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetFlexBasisValue sets flex basis in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetFlexBasisValue(flexBasis Value) {
+	value := &node.Style.FlexBasis
+	if !valueEq(*value, flexBasis) {
+		*value = flexBasis
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // NodeStyleSetFlexBasisAuto sets flex basis auto
 func NodeStyleSetFlexBasisAuto(node *Node) {
 	if node.Style.FlexBasis.Unit != UnitAuto {
@@ -307,6 +466,37 @@ func NodeStyleSetFlexBasisAuto(node *Node) {
 	}
 }
 
+// StyleSetFlexBasisMinContent sets flex basis to min-content
+func (node *Node) StyleSetFlexBasisMinContent() {
+	basis := &node.Style.FlexBasis
+	if basis.Unit != UnitMinContent {
+		basis.Value = Undefined
+		basis.Unit = UnitMinContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetFlexBasisMaxContent sets flex basis to max-content
+func (node *Node) StyleSetFlexBasisMaxContent() {
+	basis := &node.Style.FlexBasis
+	if basis.Unit != UnitMaxContent {
+		basis.Value = Undefined
+		basis.Unit = UnitMaxContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetFlexBasisFitContent sets flex basis to fit-content(limit). Undefined limit
+// fits the content to the available space.
+func (node *Node) StyleSetFlexBasisFitContent(limit float32) {
+	basis := &node.Style.FlexBasis
+	if basis.Unit != UnitFitContent || !FloatsEqual(basis.Value, limit) {
+		basis.Value = limit
+		basis.Unit = UnitFitContent
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleSetMargin sets margin
 func (node *Node) StyleSetMargin(edge Edge, margin float32) {
 	if node.Style.Margin[edge].Value != margin ||
@@ -333,6 +523,24 @@ func (node *Node) StyleSetMarginPercent(edge Edge, margin float32) {
 	}
 }
 
+// StyleSetMarginCalc sets margin to a calc expression
+func (node *Node) StyleSetMarginCalc(edge Edge, calc *Calc) {
+	value := &node.Style.Margin[edge]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetMarginValue sets margin in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetMarginValue(edge Edge, margin Value) {
+	value := &node.Style.Margin[edge]
+	if !valueEq(*value, margin) {
+		*value = margin
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetMargin gets margin
 func (node *Node) StyleGetMargin(edge Edge) Value {
 	return node.Style.Margin[edge]
@@ -373,6 +581,24 @@ func (node *Node) StyleSetPaddingPercent(edge Edge, padding float32) {
 	}
 }
 
+// StyleSetPaddingCalc sets padding to a calc expression
+func (node *Node) StyleSetPaddingCalc(edge Edge, calc *Calc) {
+	value := &node.Style.Padding[edge]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetPaddingValue sets padding in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetPaddingValue(edge Edge, padding Value) {
+	value := &node.Style.Padding[edge]
+	if !valueEq(*value, padding) {
+		*value = padding
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetPadding gets padding
 func (node *Node) StyleGetPadding(edge Edge) Value {
 	return node.Style.Padding[edge]
@@ -396,6 +622,70 @@ func (node *Node) StyleGetBorder(edge Edge) float32 {
 	return node.Style.Border[edge].Value
 }
 
+// StyleSetGap sets gap
+func (node *Node) StyleSetGap(gutter Gutter, gap float32) {
+	if node.Style.Gap[gutter].Value != gap ||
+		node.Style.Gap[gutter].Unit != UnitPoint {
+		node.Style.Gap[gutter].Value = gap
+		node.Style.Gap[gutter].Unit = UnitPoint
+		if FloatIsUndefined(gap) {
+			node.Style.Gap[gutter].Unit = UnitUndefined
+		}
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetGapPercent sets gap percent
+func (node *Node) StyleSetGapPercent(gutter Gutter, gap float32) {
+	if node.Style.Gap[gutter].Value != gap ||
+		node.Style.Gap[gutter].Unit != UnitPercent {
+		node.Style.Gap[gutter].Value = gap
+		node.Style.Gap[gutter].Unit = UnitPercent
+		if FloatIsUndefined(gap) {
+			node.Style.Gap[gutter].Unit = UnitUndefined
+		}
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetGapValue sets gap in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetGapValue(gutter Gutter, gap Value) {
+	value := &node.Style.Gap[gutter]
+	if !valueEq(*value, gap) {
+		*value = gap
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGap gets gap
+func (node *Node) StyleGetGap(gutter Gutter) Value {
+	return node.Style.Gap[gutter]
+}
+
+// StyleSetFontSize sets font size
+func (node *Node) StyleSetFontSize(fontSize float32) {
+	unit := UnitPoint
+	if FloatIsUndefined(fontSize) {
+		unit = UnitUndefined
+	}
+	node.StyleSetFontSizeValue(Value{Value: fontSize, Unit: unit})
+}
+
+// StyleSetFontSizeValue sets font size in any unit. Font size is inherited,
+// so descendants are marked dirty too.
+func (node *Node) StyleSetFontSizeValue(fontSize Value) {
+	if !valueEq(node.Style.FontSize, fontSize) {
+		node.Style.FontSize = fontSize
+		nodeClearUnitBases(node)
+		nodeMarkDirtyRecursive(node)
+	}
+}
+
+// StyleGetFontSize gets font size
+func (node *Node) StyleGetFontSize() Value {
+	return node.Style.FontSize
+}
+
 // StyleSetMinWidth sets min width
 func (node *Node) StyleSetMinWidth(minWidth float32) {
 	if node.Style.MinDimensions[DimensionWidth].Value != minWidth ||
@@ -422,6 +712,24 @@ func (node *Node) StyleSetMinWidthPercent(minWidth float32) {
 	}
 }
 
+// StyleSetMinWidthCalc sets min width to a calc expression
+func (node *Node) StyleSetMinWidthCalc(calc *Calc) {
+	value := &node.Style.MinDimensions[DimensionWidth]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetMinWidthValue sets min width in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetMinWidthValue(minWidth Value) {
+	value := &node.Style.MinDimensions[DimensionWidth]
+	if !valueEq(*value, minWidth) {
+		*value = minWidth
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetMinWidth gets min width
 func (node *Node) StyleGetMinWidth() Value {
 	return node.Style.MinDimensions[DimensionWidth]
@@ -453,6 +761,24 @@ func (node *Node) StyleSetMinHeightPercent(minHeight float32) {
 	}
 }
 
+// StyleSetMinHeightCalc sets min height to a calc expression
+func (node *Node) StyleSetMinHeightCalc(calc *Calc) {
+	value := &node.Style.MinDimensions[DimensionHeight]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetMinHeightValue sets min height in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetMinHeightValue(minHeight Value) {
+	value := &node.Style.MinDimensions[DimensionHeight]
+	if !valueEq(*value, minHeight) {
+		*value = minHeight
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetMinHeight gets min height
 func (node *Node) StyleGetMinHeight() Value {
 	return node.Style.MinDimensions[DimensionHeight]
@@ -484,6 +810,24 @@ func (node *Node) StyleSetMaxWidthPercent(maxWidth float32) {
 	}
 }
 
+// StyleSetMaxWidthCalc sets max width to a calc expression
+func (node *Node) StyleSetMaxWidthCalc(calc *Calc) {
+	value := &node.Style.MaxDimensions[DimensionWidth]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetMaxWidthValue sets max width in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetMaxWidthValue(maxWidth Value) {
+	value := &node.Style.MaxDimensions[DimensionWidth]
+	if !valueEq(*value, maxWidth) {
+		*value = maxWidth
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetMaxWidth gets max width
 func (node *Node) StyleGetMaxWidth() Value {
 	return node.Style.MaxDimensions[DimensionWidth]
@@ -515,6 +859,24 @@ func (node *Node) StyleSetMaxHeightPercent(maxHeight float32) {
 	}
 }
 
+// StyleSetMaxHeightCalc sets max height to a calc expression
+func (node *Node) StyleSetMaxHeightCalc(calc *Calc) {
+	value := &node.Style.MaxDimensions[DimensionHeight]
+	if value.Unit != UnitCalc || !calcEqual(value.Calc, calc) {
+		*value = ValueCalc(calc)
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetMaxHeightValue sets max height in any unit, like UnitVw or UnitEm
+func (node *Node) StyleSetMaxHeightValue(maxHeight Value) {
+	value := &node.Style.MaxDimensions[DimensionHeight]
+	if !valueEq(*value, maxHeight) {
+		*value = maxHeight
+		nodeMarkDirtyInternal(node)
+	}
+}
+
 // StyleGetMaxHeight gets max height
 func (node *Node) StyleGetMaxHeight() Value {
 	return node.Style.MaxDimensions[DimensionHeight]
@@ -528,6 +890,18 @@ func (node *Node) StyleSetAspectRatio(aspectRatio float32) {
 	}
 }
 
+// HasNewLayout returns true if the layout of node was computed since the flag
+// was last reset
+func (node *Node) HasNewLayout() bool {
+	return node.hasNewLayout
+}
+
+// SetHasNewLayout sets the has new layout flag, which is usually reset after
+// applying the new layout
+func (node *Node) SetHasNewLayout(hasNewLayout bool) {
+	node.hasNewLayout = hasNewLayout
+}
+
 // LayoutGetLeft gets left
 func (node *Node) LayoutGetLeft() float32 {
 	return node.Layout.Position[EdgeLeft]
@@ -560,7 +934,7 @@ func (node *Node) LayoutGetHeight() float32 {
 
 // LayoutGetMargin gets margin
 func (node *Node) LayoutGetMargin(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd, "Cannot get layout properties of multi-edge shorthands")
+	assertWithNode(node, edge < EdgeEnd, ErrMultiEdgeLayout)
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Margin[EdgeEnd]
@@ -578,8 +952,7 @@ func (node *Node) LayoutGetMargin(edge Edge) float32 {
 
 // LayoutGetBorder gets border
 func (node *Node) LayoutGetBorder(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd,
-		"Cannot get layout properties of multi-edge shorthands")
+	assertWithNode(node, edge < EdgeEnd, ErrMultiEdgeLayout)
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Border[EdgeEnd]
@@ -597,8 +970,7 @@ func (node *Node) LayoutGetBorder(edge Edge) float32 {
 
 // LayoutGetPadding gets padding
 func (node *Node) LayoutGetPadding(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd,
-		"Cannot get layout properties of multi-edge shorthands")
+	assertWithNode(node, edge < EdgeEnd, ErrMultiEdgeLayout)
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Padding[EdgeEnd]
@@ -613,3 +985,35 @@ func (node *Node) LayoutGetPadding(edge Edge) float32 {
 	}
 	return node.Layout.Padding[edge]
 }
+
+// LayoutGetBox returns box of node relative to its parent. The left and right
+// sizes of edges are resolved with the layout direction.
+func (node *Node) LayoutGetBox(box Box) Rect {
+	r := Rect{
+		Left:   node.Layout.Position[EdgeLeft],
+		Top:    node.Layout.Position[EdgeTop],
+		Width:  node.Layout.Dimensions[DimensionWidth],
+		Height: node.Layout.Dimensions[DimensionHeight],
+	}
+	switch box {
+	case BoxPadding:
+		r = r.inset(node.LayoutGetBorder)
+	case BoxContent:
+		r = r.inset(node.LayoutGetBorder).inset(node.LayoutGetPadding)
+	case BoxMargin:
+		r = r.inset(func(edge Edge) float32 { return -node.LayoutGetMargin(edge) })
+	}
+	return r
+}
+
+// LayoutGetAbsoluteBox returns box of node in the coordinates of its root,
+// which are the coordinates the position of the root is relative to, as
+// used by HitTest
+func (node *Node) LayoutGetAbsoluteBox(box Box) Rect {
+	r := node.LayoutGetBox(box)
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		r.Left += parent.Layout.Position[EdgeLeft]
+		r.Top += parent.Layout.Position[EdgeTop]
+	}
+	return r
+}